@@ -1,6 +1,7 @@
 package lex
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/zalgonoise/gio"
@@ -13,33 +14,139 @@ const (
 )
 
 // LexBuffer implements the Lexer interface, by accepting a gio.Reader of any type
+//
+// The units read from the gio.Reader are kept in a buffer that is cut every time an item is
+// emitted, keeping only a few look-back units. This means that its cursor and starting point
+// (as well as the `Extract()` indices) are relative to the current buffer, while the positions
+// in the emitted items are absolute (as the index of the unit in the input data stream)
 type LexBuffer[C comparable, T any] struct {
 	input              gio.Reader[T]
 	buf                []T
+	mem                []T
+	offset             int
 	start              int
 	pos                int
 	state              StateFn[C, T]
 	items              chan Item[C, T]
 	bufferLookbackSize int
+
+	emitted  int
+	guard    guard
+	fault    *Item[C, T]
+	recovery bool
+	trivia   trivia[C, T]
+	channels channels[C, T]
+	init     StateFn[C, T]
+	hint     any
+	eof      T
+	tracer   Tracer[C, T]
+	keep     int
+
+	bufferCap   int
+	bufferThres int
 }
 
 var _ Lexer[uint8, any] = &LexBuffer[uint8, any]{}
 
+// NewBuffer creates a new buffered lexer with the base / starting StateFn and input gio.Reader
+//
+// It returns nil if the input gio.Reader is nil. To configure the lexer with Options, use
+// NewLexBuffer
 func NewBuffer[C comparable, T any](
 	initFn StateFn[C, T],
 	input gio.Reader[T],
 ) *LexBuffer[C, T] {
+	l, _ := NewLexBuffer(initFn, input)
+	return l
+}
+
+// NewLexBuffer creates a new buffered lexer with the base / starting StateFn and input
+// gio.Reader, configured with the input Options `opts`
+//
+// It returns an error if the input gio.Reader is nil (ErrNilReader), or if any of the Options
+// is invalid
+func NewLexBuffer[C comparable, T any](
+	initFn StateFn[C, T],
+	input gio.Reader[T],
+	opts ...Option,
+) (*LexBuffer[C, T], error) {
 	if input == nil {
-		return nil
+		return nil, ErrNilReader
+	}
+	return newLexBuffer(initFn, input, opts)
+}
+
+// newLexBuffer creates a new buffered lexer with the base / starting StateFn and input
+// gio.Reader, configured with the input Options `opts`; without verifying the input gio.Reader
+func newLexBuffer[C comparable, T any](
+	initFn StateFn[C, T],
+	input gio.Reader[T],
+	opts []Option,
+) (*LexBuffer[C, T], error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
 	}
+	eof, tracer, err := typed[C, T](o)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]T, 0, o.bufferCap)
 
 	return &LexBuffer[C, T]{
 		input:              input,
-		buf:                make([]T, 0, bufferInitCap),
+		buf:                buf,
+		mem:                buf,
 		state:              initFn,
-		items:              make(chan Item[C, T], 2),
-		bufferLookbackSize: bufferLookbackSize,
+		init:               initFn,
+		items:              make(chan Item[C, T], o.queueSize),
+		bufferLookbackSize: o.lookback,
+		bufferCap:          o.bufferCap,
+		bufferThres:        o.bufferThres,
+		guard:              guard{limits: o.limits},
+		recovery:           o.recovery,
+		eof:                eof,
+		tracer:             tracer,
+	}, nil
+}
+
+// Reset prepares the lexer to lex the input gio.Reader `input` from the start, with the base /
+// starting StateFn `initFn`; reusing its items queue and buffer, so that lexing many small
+// inputs does not allocate a new lexer for each of them
+//
+// The lexer's configuration (its Options, Limits, TriviaMode, channel routes and look-back size)
+// is kept, while its state (the buffered units, the queued and hidden items, the pending trivia,
+// the hint and the counted StateFn transitions) is discarded. As the buffer is reused, the values
+// of the items returned before the reset must not be used afterwards
+//
+// It returns ErrNilReader if the input gio.Reader is nil, leaving the lexer unchanged
+func (l *LexBuffer[C, T]) Reset(initFn StateFn[C, T], input gio.Reader[T]) error {
+	if input == nil {
+		return ErrNilReader
 	}
+	l.reset(initFn, input)
+	return nil
+}
+
+// reset discards the lexer's state, setting it up with the StateFn `initFn` and the
+// gio.Reader `input`
+func (l *LexBuffer[C, T]) reset(initFn StateFn[C, T], input gio.Reader[T]) {
+	drain(l.items)
+	l.input = input
+	l.buf = l.mem[:0]
+	l.offset = 0
+	l.start = 0
+	l.pos = 0
+	l.keep = 0
+	l.state = initFn
+	l.init = initFn
+	l.emitted = 0
+	l.guard.reset()
+	l.fault = nil
+	l.trivia.reset(nil)
+	l.channels.reset()
+	l.hint = nil
 }
 
 // Size sets a custom buffer look-back size whenever an item is emited
@@ -59,22 +166,183 @@ func (l *LexBuffer[C, T]) Size(maxSize int) {
 // Note that multiple calls to `NextItem()` should be made when tokenizing input data;
 // usually in a for-loop while the output item is not EOF.
 func (l *LexBuffer[C, T]) NextItem() Item[C, T] {
-	var next Item[C, T]
+	item := l.next()
+	// keep the units of the returned item buffered, so that it can be relexed
+	l.keep = item.Pos
+	if l.tracer != nil {
+		l.tracer.Item(item)
+	}
+	return item
+}
+
+// next returns the next queued item, running the StateFns until one is emitted
+func (l *LexBuffer[C, T]) next() Item[C, T] {
 	for {
 		select {
-		case next = <-l.items:
+		case next := <-l.items:
 			return next
 		default:
 			if l.state != nil {
-				l.state = l.state(l)
+				l.step()
 				continue
 			}
-			close(l.items)
-			return next
+			if item, ok := l.trivia.flush(); ok {
+				return item
+			}
+			if l.fault != nil {
+				fault := *l.fault
+				l.fault = nil
+				return l.trivia.eof(fault)
+			}
+			// no more StateFns to run: return an EOF item on the cursor's position
+			return l.trivia.eof(Item[C, T]{Pos: l.offset + l.pos})
 		}
 	}
 }
 
+// step runs the current StateFn, verifying the lexer's Limits before and after the transition
+//
+// If a limit is exceeded (or a panic is recovered), the lexer is stopped with a diagnostic EOF
+// item carrying a *StateError
+func (l *LexBuffer[C, T]) step() {
+	if err := l.guard.before(); err != nil {
+		l.stop(err, l.state)
+		return
+	}
+
+	state := l.state
+	if l.tracer != nil {
+		l.tracer.State(stateName(state), l.offset+l.pos)
+	}
+	next, err := run[C, T](l, state, l.recovery)
+	if err != nil {
+		l.stop(err, state)
+		return
+	}
+	l.state = next
+
+	if err := l.guard.after(l.offset+l.start, l.offset+l.pos, l.emitted); err != nil {
+		l.stop(err, state)
+	}
+}
+
+// stop halts the lexer, setting up a diagnostic EOF item with the error `err`, raised
+// in the StateFn `state`, positioned on the lexer's starting point
+func (l *LexBuffer[C, T]) stop(err error, state StateFn[C, T]) {
+	l.state = nil
+	l.fault = &Item[C, T]{
+		Pos: l.offset + l.start,
+		Err: &StateError{
+			Err:   err,
+			State: stateName(state),
+			Pos:   l.offset + l.pos,
+		},
+	}
+}
+
+// Limit sets the runtime limits for the lexer, that stop it with a diagnostic EOF item
+// (carrying a *StateError) when exceeded
+func (l *LexBuffer[C, T]) Limit(limits Limits) {
+	l.guard.limits = limits
+}
+
+// Recover enables or disables the recovery of panics raised by the StateFns
+//
+// When enabled, a panic stops the lexer with a diagnostic EOF item carrying a *StateError that
+// wraps a *PanicError, with the recovered value and stack trace
+func (l *LexBuffer[C, T]) Recover(enabled bool) {
+	l.recovery = enabled
+}
+
+// Trivia sets the lexer's TriviaMode, defining whether the units skipped with `Ignore()` are
+// discarded or attached to the emitted items (as Leading or Trailing trivia). The values of the
+// items emitted on a hidden channel are attached as trivia too
+func (l *LexBuffer[C, T]) Trivia(mode TriviaMode) {
+	l.trivia.mode = mode
+}
+
+// Route sets the default channel for the items of token type `itemType`, when emitted with
+// `Emit()`
+func (l *LexBuffer[C, T]) Route(itemType C, channel Channel) {
+	l.channels.set(itemType, channel)
+}
+
+// Hidden returns the items emitted on channels other than the DefaultChannel so far, in the
+// order they were emitted; or only the ones on the input channels `chs`, if any are provided
+func (l *LexBuffer[C, T]) Hidden(chs ...Channel) []Item[C, T] {
+	return l.channels.items(chs...)
+}
+
+// Hint returns the hint set by the lexer's consumer (such as a parser), to lex
+// context-sensitive tokens; or nil if none is set
+func (l *LexBuffer[C, T]) Hint() any {
+	return l.hint
+}
+
+// Enter marks the start of a nesting level (such as an opening bracket), returning true
+//
+// If the lexer's MaxDepth limit is exceeded, it returns false, and the lexer is stopped once
+// the current StateFn returns, with a diagnostic EOF item wrapping ErrMaxDepth
+func (l *LexBuffer[C, T]) Enter() bool {
+	return l.guard.enter() == nil
+}
+
+// Leave marks the end of a nesting level, entered with `Enter()`
+func (l *LexBuffer[C, T]) Leave() {
+	l.guard.leave()
+}
+
+// Depth returns the current nesting level, as set with `Enter()` and `Leave()`
+func (l *LexBuffer[C, T]) Depth() int {
+	return l.guard.depth
+}
+
+// SetHint sets the hint `hint` for the lexer's StateFns to read with `Hint()`, allowing the
+// lexer's consumer (such as a parser) to provide context for context-sensitive tokens, like
+// `/` as a division operator versus the start of a regular expression
+//
+// The hint is kept until it is set again, and is only read by the StateFns that run after it
+// is set. Items already queued by the lexer can be lexed again with `Relex()`
+func (l *LexBuffer[C, T]) SetHint(hint any) {
+	l.hint = hint
+}
+
+// Relex rewinds the lexer to the position of the item `item` (usually, the last item returned
+// by `NextItem()`), so that its units are lexed again with the StateFn `state`, or the lexer's
+// starting StateFn if nil; such as after setting a new hint with `SetHint()`
+//
+// Any items emitted after `item` are discarded, and any lookahead items held by the consumer
+// must be discarded too. If the lexer no longer holds the units for `item`, an error wrapping
+// ErrRelex is returned
+//
+// A LexBuffer keeps the units from the last item returned by `NextItem()` onwards in its buffer,
+// so that item (or any item after it) can always be relexed; while earlier items may have been
+// cut off from the buffer already. If items were emitted on a hidden channel after it, only the
+// units from the last hidden item onwards are kept
+func (l *LexBuffer[C, T]) Relex(item Item[C, T], state StateFn[C, T]) error {
+	idx := item.Pos - l.offset
+	if idx > l.pos {
+		return fmt.Errorf("%w: position %d is out of range", ErrRelex, item.Pos)
+	}
+	if idx < 0 {
+		return fmt.Errorf("%w: position %d is no longer buffered", ErrRelex, item.Pos)
+	}
+
+	if state == nil {
+		state = l.init
+	}
+
+	drain(l.items)
+	l.trivia.reset(item.Leading)
+	l.channels.truncate(item.Pos)
+	l.fault = nil
+	l.state = state
+	l.start = idx
+	l.pos = idx
+	l.guard.rewind(item.Pos)
+	return nil
+}
+
 // Emit pushes the set of units identified by token `itemType` to the items channel,
 // that returns it in the NextItem() method.
 //
@@ -82,38 +350,188 @@ func (l *LexBuffer[C, T]) NextItem() Item[C, T] {
 // starting index to the current position index.
 //
 // It also sets the lexer's starting index to the current position index.
+//
+// The item is pushed to the token type's default channel, as set with `Route()`; which is the
+// DefaultChannel unless set otherwise
 func (l *LexBuffer[C, T]) Emit(itemType C) {
-	l.items <- Item[C, T]{
-		Pos:   l.start,
-		Type:  itemType,
-		Value: l.buf[l.start:l.pos],
+	l.EmitOn(l.channels.route(itemType), itemType)
+}
+
+// EmitOn works like `Emit()`, but pushes the item to the channel `channel` instead of the
+// token type's default channel
+//
+// Items on a channel other than the DefaultChannel are not returned by `NextItem()`, but are
+// kept aside, and can be retrieved with the `Hidden()` method
+func (l *LexBuffer[C, T]) EmitOn(channel Channel, itemType C) {
+	l.emitted++
+	if !l.push(Item[C, T]{
+		Pos:     l.offset + l.start,
+		Type:    itemType,
+		Value:   l.buf[l.start:l.pos],
+		Channel: channel,
+	}, l.pos-l.start) {
+		return
 	}
-	// cutoff the buffer's head up to the current position
-	l.buf = l.buf[l.pos:]
-	l.start = 0
-	l.pos = 0
+	l.cut()
+}
+
+// EmitRange pushes the units from index `start` to index `end` (in the same reference as
+// `Extract()`), identified by token `itemType`, positioned on index `start`
+//
+// The indices are clamped to the bounds of the input, as with `Extract()`. It does not change
+// the lexer's starting point nor its position
+func (l *LexBuffer[C, T]) EmitRange(itemType C, start, end int) {
+	l.emitted++
+	_ = l.consume(end - 1)
+	if start < 0 {
+		start = 0
+	}
+	if start > len(l.buf) {
+		start = len(l.buf)
+	}
+	if end > len(l.buf) {
+		end = len(l.buf)
+	}
+	if end < start {
+		end = start
+	}
+	_ = l.push(Item[C, T]{
+		Pos:     l.offset + start,
+		Type:    itemType,
+		Value:   l.buf[start:end],
+		Channel: l.channels.route(itemType),
+	}, end-start)
+}
+
+// EmitEmpty pushes a zero-width item identified by token `itemType`, positioned on the
+// lexer's starting point; such as an inserted separator, or an INDENT / DEDENT token
+//
+// It does not change the lexer's starting point nor its position
+func (l *LexBuffer[C, T]) EmitEmpty(itemType C) {
+	l.emitted++
+	_ = l.push(Item[C, T]{
+		Pos:     l.offset + l.start,
+		Type:    itemType,
+		Channel: l.channels.route(itemType),
+	}, 0)
+}
+
+// EmitValue pushes an item identified by token `itemType` with the value `value`, positioned
+// on the lexer's starting point; such as a string literal with its escape sequences decoded
+//
+// Like `Emit()`, it consumes the units from the lexer's starting index to the current
+// position index, setting the starting index to the current position index
+func (l *LexBuffer[C, T]) EmitValue(itemType C, value []T) {
+	l.emitted++
+	if !l.push(Item[C, T]{
+		Pos:     l.offset + l.start,
+		Type:    itemType,
+		Value:   value,
+		Channel: l.channels.route(itemType),
+	}, l.pos-l.start) {
+		return
+	}
+	l.cut()
+}
+
+// EmitData works like `Emit()`, but also sets the item's Data to `data`; such as a number
+// parsed from the item's units, or a keyword ID
+//
+// The data travels with the item through any Emitter (and into a parse.Tree's nodes), and can
+// be read as a typed value with the Data function
+func (l *LexBuffer[C, T]) EmitData(itemType C, data any) {
+	l.emitted++
+	if !l.push(Item[C, T]{
+		Pos:     l.offset + l.start,
+		Type:    itemType,
+		Value:   l.buf[l.start:l.pos],
+		Channel: l.channels.route(itemType),
+		Data:    data,
+	}, l.pos-l.start) {
+		return
+	}
+	l.cut()
+}
+
+// EmitError pushes the set of units from the lexer's starting index to the current position
+// index, identified by token `itemType` and carrying the error `err`
+//
+// Like `Emit()`, it sets the lexer's starting index to the current position index
+func (l *LexBuffer[C, T]) EmitError(itemType C, err error) {
+	l.emitted++
+	if !l.push(Item[C, T]{
+		Pos:     l.offset + l.start,
+		Type:    itemType,
+		Value:   l.buf[l.start:l.pos],
+		Err:     err,
+		Channel: l.channels.route(itemType),
+	}, l.pos-l.start) {
+		return
+	}
+	l.cut()
+}
+
+// push sends the item `item` to its channel: either to the items channel, returned in the
+// NextItem() method, or kept aside as a hidden item
+//
+// It returns false if the item was dropped, for spanning over `width` units of the input exceeding
+// the lexer's MaxWidth, or for exceeding its MaxItems; which stops the lexer once the current
+// StateFn returns, with a diagnostic EOF item positioned on the dropped item
+func (l *LexBuffer[C, T]) push(item Item[C, T], width int) bool {
+	if l.guard.item(width) != nil {
+		return false
+	}
+	if item.Channel != DefaultChannel {
+		l.channels.hidden = append(l.channels.hidden, item)
+		l.trivia.hide(item)
+		// hidden items are not returned by NextItem(), so they move the held back units forward;
+		// otherwise a run of hidden items would keep the whole run buffered
+		l.keep = item.Pos
+		return true
+	}
+	if item, ok := l.trivia.attach(item); ok {
+		l.items <- item
+	}
+	return true
+}
+
+// cut sets the lexer's starting index to the current position index, cutting off the
+// buffer's head up to the current position, minus the look-back units; while holding back the
+// units from the last item returned by `NextItem()`
+func (l *LexBuffer[C, T]) cut() {
+	cut := l.pos - l.bufferLookbackSize
+	if keep := l.keep - l.offset; keep < cut {
+		// hold back the units from the last item returned by NextItem(), for `Relex()`
+		cut = keep
+	}
+	if cut < 0 {
+		cut = 0
+	}
+	l.buf = l.buf[cut:]
+	l.offset += cut
+	l.pos -= cut
+	l.start = l.pos
 
 	// look into the buffer's remaining capacity
 	// if below the set capacity threshold, use a new buffer
 	//
 	// saves over 200 allocs/op on `SqueezeTheBuffer` test by avoiding
 	// repeated calls to grow the slice
-	if cap(l.buf) < bufferCapThres {
-		if len(l.buf)-l.bufferLookbackSize < 0 {
-			b := make([]T, len(l.buf), bufferInitCap)
-			copy(b, l.buf)
-			l.buf = b
-			return
+	if cap(l.buf) < l.bufferThres {
+		size := l.bufferCap
+		if len(l.buf) > size {
+			size = len(l.buf) * 2
 		}
-
-		b := make([]T, l.bufferLookbackSize, bufferInitCap)
-		copy(b, l.buf[len(l.buf)-l.bufferLookbackSize:])
+		b := make([]T, len(l.buf), size)
+		copy(b, l.buf)
 		l.buf = b
+		l.mem = b
 	}
 }
 
 // Ignore will set the starting point as the current position, ignoring any preceeding units
 func (l *LexBuffer[C, T]) Ignore() {
+	l.trivia.ignore(l.buf[l.start:l.pos])
 	l.start = l.pos
 }
 
@@ -143,9 +561,16 @@ func (l *LexBuffer[C, T]) Check(verifFn func(item T) bool) bool {
 //
 // If the validation passes, the cursor has moved one step forward (the unit was consumed)
 //
-// If the validation fails, the cursor rolls back one step
+// If the validation fails, the cursor rolls back one step; and if the cursor is already at the
+// end of the input, nothing is consumed and false is returned
 func (l *LexBuffer[C, T]) Accept(verifFn func(item T) bool) bool {
-	if ok := verifFn(l.Next()); ok {
+	pos := l.pos
+	ok := verifFn(l.Next())
+	if l.pos == pos {
+		// EOF: nothing was consumed
+		return false
+	}
+	if ok {
 		return true
 	}
 	l.Prev()
@@ -156,25 +581,79 @@ func (l *LexBuffer[C, T]) Accept(verifFn func(item T) bool) bool {
 // function as a validator
 //
 // Once it fails the verification, the cursor is rolledback once, leaving the caller at the unit
-// that failed the verifFn
+// that failed the verifFn; or it stops at the end of the input
 func (l *LexBuffer[C, T]) AcceptRun(verifFn func(item T) bool) {
-	for verifFn(l.Next()) {
+	for {
+		pos := l.pos
+		ok := verifFn(l.Next())
+		if l.pos == pos {
+			// EOF: nothing was consumed
+			return
+		}
+		if !ok {
+			l.Prev()
+			return
+		}
+	}
+}
+
+// AcceptUntil consumes all units up to (but not including) the first unit that passes the
+// `verifFn` validator, returning true
+//
+// If the end of the input is reached first, the cursor is rolled back to where it was,
+// and false is returned
+func (l *LexBuffer[C, T]) AcceptUntil(verifFn func(item T) bool) bool {
+	pos := l.pos
+	for {
+		next := l.pos
+		item := l.Next()
+		if l.pos == next {
+			// EOF
+			l.pos = pos
+			return false
+		}
+		if verifFn(item) {
+			l.pos = next
+			return true
+		}
 	}
-	l.Prev()
 }
 
+// AcceptN consumes exactly `n` units that pass the `verifFn` validator, returning true
+//
+// If any of them fails the validation (or the end of the input is reached), the cursor is
+// rolled back to where it was, and false is returned
+func (l *LexBuffer[C, T]) AcceptN(n int, verifFn func(item T) bool) bool {
+	pos := l.pos
+	for i := 0; i < n; i++ {
+		next := l.pos
+		if item := l.Next(); l.pos == next || !verifFn(item) {
+			l.pos = pos
+			return false
+		}
+	}
+	return true
+}
+
+// consume reads units from the input gio.Reader, one at a time, until the buffer
+// holds the unit on index `pos`
+//
+// It stops reading if that would exceed the lexer's MaxWidth or MaxBuffered limits, returning
+// the limit's error; as if the end of the input was reached
 func (l *LexBuffer[C, T]) consume(pos int) error {
-	if n := pos - len(l.buf) + 1; n > 0 {
-		for i := 0; i < n; i++ {
-			var next = make([]T, 1)
-			n, err := l.input.Read(next)
-			if err != nil {
-				return err
-			}
-			if n == 0 {
-				return io.EOF
+	var zero T
+	for len(l.buf) <= pos {
+		if err := l.guard.read(len(l.buf), len(l.buf)-l.start); err != nil {
+			return err
+		}
+		l.buf = append(l.buf, zero)
+		n, err := l.input.Read(l.buf[len(l.buf)-1:])
+		if n == 0 {
+			l.buf = l.buf[:len(l.buf)-1]
+			if err == nil {
+				err = io.EOF
 			}
-			l.buf = append(l.buf, next[0])
+			return err
 		}
 	}
 	return nil
@@ -184,9 +663,9 @@ func (l *LexBuffer[C, T]) consume(pos int) error {
 //
 // If the position is already over the size of the input, the zero-value EOF token is returned
 func (l *LexBuffer[C, T]) Cur() T {
-	if l.pos >= len(l.buf) {
-		var eof T
-		return eof
+	err := l.consume(l.pos)
+	if err != nil {
+		return l.eof
 	}
 	return l.buf[l.pos]
 }
@@ -207,12 +686,11 @@ func (l *LexBuffer[C, T]) Len() int {
 // If the position is bigger or equal to the size of the input data, the position
 // value is NOT incremented and the zero-value EOF token is returned
 func (l *LexBuffer[C, T]) Next() T {
-	l.pos++
 	err := l.consume(l.pos)
 	if err != nil {
-		var eof T
-		return eof
+		return l.eof
 	}
+	l.pos++
 	return l.buf[l.pos-1]
 }
 
@@ -223,8 +701,7 @@ func (l *LexBuffer[C, T]) Next() T {
 // and the zero-value EOF token is returned
 func (l *LexBuffer[C, T]) Prev() T {
 	if l.pos-1 < 0 {
-		var eof T
-		return eof
+		return l.eof
 	}
 	l.pos--
 	for l.pos < l.start {
@@ -232,8 +709,7 @@ func (l *LexBuffer[C, T]) Prev() T {
 	}
 	err := l.consume(l.pos)
 	if err != nil {
-		var eof T
-		return eof
+		return l.eof
 	}
 	return l.buf[l.pos]
 }
@@ -242,14 +718,11 @@ func (l *LexBuffer[C, T]) Prev() T {
 //
 // If the next token overflows the input's index, the zero-value EOF token is returned
 func (l *LexBuffer[C, T]) Peek() T {
-	err := l.consume(l.pos)
+	err := l.consume(l.pos + 1)
 	if err != nil {
-		var eof T
-		return eof
+		return l.eof
 	}
-	next := l.buf[l.pos]
-	l.pos--
-	return next
+	return l.buf[l.pos+1]
 }
 
 // Head returns to the beginning of the buffer, setting both lexer's start and position
@@ -257,6 +730,10 @@ func (l *LexBuffer[C, T]) Peek() T {
 func (l *LexBuffer[C, T]) Head() T {
 	l.pos = 0
 	l.start = 0
+	err := l.consume(l.pos)
+	if err != nil {
+		return l.eof
+	}
 	return l.buf[l.pos]
 }
 
@@ -264,8 +741,7 @@ func (l *LexBuffer[C, T]) Head() T {
 // the last item in the input
 func (l *LexBuffer[C, T]) Tail() T {
 	if len(l.buf) == 0 {
-		var eof T
-		return eof
+		return l.eof
 	}
 
 	l.pos = len(l.buf) - 1
@@ -280,22 +756,16 @@ func (l *LexBuffer[C, T]) Tail() T {
 // zero-value EOF token is returned
 func (l *LexBuffer[C, T]) Idx(idx int) T {
 	if idx < 0 {
-		var eof T
-		return eof
+		return l.eof
 	}
-	if idx >= len(l.buf) {
-		var eof T
-		return eof
+	err := l.consume(idx)
+	if err != nil {
+		return l.eof
 	}
 	l.pos = idx
 	if idx < l.start {
 		l.start = idx
 	}
-	err := l.consume(l.pos)
-	if err != nil {
-		var eof T
-		return eof
-	}
 
 	return l.buf[l.pos]
 }
@@ -308,22 +778,16 @@ func (l *LexBuffer[C, T]) Idx(idx int) T {
 // zero-value EOF token is returned
 func (l *LexBuffer[C, T]) Offset(amount int) T {
 	if l.pos+amount < 0 {
-		var eof T
-		return eof
+		return l.eof
 	}
-	if l.pos+amount >= len(l.buf) {
-		var eof T
-		return eof
+	err := l.consume(l.pos + amount)
+	if err != nil {
+		return l.eof
 	}
 	l.pos += amount
 	if l.pos-l.start < 0 {
 		l.start += amount
 	}
-	err := l.consume(l.pos)
-	if err != nil {
-		var eof T
-		return eof
-	}
 
 	return l.buf[l.pos]
 }
@@ -335,18 +799,12 @@ func (l *LexBuffer[C, T]) Offset(amount int) T {
 // If the input index is greater than the size of the input, the
 // zero-value EOF token is returned
 func (l *LexBuffer[C, T]) PeekIdx(idx int) T {
-	if idx >= len(l.buf) {
-		var eof T
-		return eof
-	}
 	if idx < 0 {
-		var eof T
-		return eof
+		return l.eof
 	}
 	err := l.consume(idx)
 	if err != nil {
-		var eof T
-		return eof
+		return l.eof
 	}
 
 	return l.buf[idx]
@@ -359,14 +817,12 @@ func (l *LexBuffer[C, T]) PeekIdx(idx int) T {
 // If the result offset is greater than the size of the slice, the
 // zero-value EOF token is returned
 func (l *LexBuffer[C, T]) PeekOffset(amount int) T {
-	if l.pos+amount >= len(l.buf) || l.pos+amount < 0 {
-		var eof T
-		return eof
+	if l.pos+amount < 0 {
+		return l.eof
 	}
-	err := l.consume((l.pos + amount))
+	err := l.consume(l.pos + amount)
 	if err != nil {
-		var eof T
-		return eof
+		return l.eof
 	}
 
 	return l.buf[l.pos+amount]
@@ -0,0 +1,172 @@
+package lex
+
+// ChannelEmitter describes a lexer that can emit items on a channel other than their token
+// type's default channel
+type ChannelEmitter[C comparable, T any] interface {
+	// EmitOn works like `Emit()`, but pushes the item to the channel `channel` instead of the
+	// token type's default channel
+	//
+	// Items on a channel other than the DefaultChannel are not returned by `NextItem()`
+	EmitOn(channel Channel, itemType C)
+}
+
+// RangeEmitter describes a lexer that can emit items over units other than the ones from its
+// starting index to its current position index
+type RangeEmitter[C comparable, T any] interface {
+	// EmitRange pushes the units from index `start` to index `end` (in the same reference as
+	// `Extract()`), identified by token `itemType`, positioned on index `start`
+	//
+	// It does not change the lexer's starting point nor its position
+	EmitRange(itemType C, start, end int)
+
+	// EmitEmpty pushes a zero-width item identified by token `itemType`, positioned on the
+	// lexer's starting point; such as an inserted separator, or an INDENT / DEDENT token
+	//
+	// It does not change the lexer's starting point nor its position
+	EmitEmpty(itemType C)
+}
+
+// ValueEmitter describes a lexer that can emit items carrying a value, data or error besides
+// their units
+type ValueEmitter[C comparable, T any] interface {
+	// EmitValue pushes an item identified by token `itemType` with the value `value`, positioned
+	// on the lexer's starting point; such as a string literal with its escape sequences decoded
+	//
+	// Like `Emit()`, it consumes the units from the lexer's starting index to the current
+	// position index, setting the starting index to the current position index
+	EmitValue(itemType C, value []T)
+
+	// EmitData works like `Emit()`, but also sets the item's Data to `data`; such as a number
+	// parsed from the item's units, or a keyword ID
+	EmitData(itemType C, data any)
+
+	// EmitError pushes the set of units from the lexer's starting index to the current position
+	// index, identified by token `itemType` and carrying the error `err`
+	//
+	// Like `Emit()`, it sets the lexer's starting index to the current position index
+	EmitError(itemType C, err error)
+}
+
+// HintReader describes a lexer that exposes the hint set by its consumer to its StateFns, as
+// set through its Hinter
+type HintReader interface {
+	// Hint returns the hint set by the lexer's consumer (such as a parser), to lex
+	// context-sensitive tokens; or nil if none is set
+	Hint() any
+}
+
+// Nester describes a lexer that keeps track of nesting levels, bound by its MaxDepth limit
+type Nester interface {
+	// Enter marks the start of a nesting level (such as an opening bracket), returning true
+	//
+	// If the lexer's MaxDepth limit is exceeded, it returns false, and the lexer is stopped once
+	// the current StateFn returns
+	Enter() bool
+
+	// Leave marks the end of a nesting level, entered with `Enter()`
+	Leave()
+
+	// Depth returns the current nesting level, as set with `Enter()` and `Leave()`
+	Depth() int
+}
+
+// Acceptor describes a lexer that can consume runs of units bound by a validator or a count
+type Acceptor[T any] interface {
+	// AcceptUntil consumes all units up to (but not including) the first unit that passes the
+	// `verifFn` validator, returning true
+	//
+	// If the end of the input is reached first, the cursor is rolled back to where it was,
+	// and false is returned
+	AcceptUntil(verifFn func(item T) bool) bool
+
+	// AcceptN consumes exactly `n` units that pass the `verifFn` validator, returning true
+	//
+	// If any of them fails the validation (or the end of the input is reached), the cursor is
+	// rolled back to where it was, and false is returned
+	AcceptN(n int, verifFn func(item T) bool) bool
+}
+
+var (
+	_ ChannelEmitter[uint8, any] = &Lex[uint8, any]{}
+	_ ChannelEmitter[uint8, any] = &LexBuffer[uint8, any]{}
+	_ RangeEmitter[uint8, any]   = &Lex[uint8, any]{}
+	_ RangeEmitter[uint8, any]   = &LexBuffer[uint8, any]{}
+	_ ValueEmitter[uint8, any]   = &Lex[uint8, any]{}
+	_ ValueEmitter[uint8, any]   = &LexBuffer[uint8, any]{}
+	_ HintReader                 = &Lex[uint8, any]{}
+	_ HintReader                 = &LexBuffer[uint8, any]{}
+	_ Nester                     = &Lex[uint8, any]{}
+	_ Nester                     = &LexBuffer[uint8, any]{}
+	_ Acceptor[any]              = &Lex[uint8, any]{}
+	_ Acceptor[any]              = &LexBuffer[uint8, any]{}
+)
+
+// emitEmpty pushes a zero-width item with the lexer's `EmitEmpty()` method if it is a
+// RangeEmitter; or with its `Emit()` method otherwise, once its pending units are ignored
+func emitEmpty[C comparable, T any](l Lexer[C, T], itemType C) {
+	if e, ok := l.(RangeEmitter[C, T]); ok {
+		e.EmitEmpty(itemType)
+		return
+	}
+	l.Ignore()
+	l.Emit(itemType)
+}
+
+// emitData pushes an item carrying the data `data` with the lexer's `EmitData()` method if it
+// is a ValueEmitter; or with its `Emit()` method otherwise, without the data
+func emitData[C comparable, T any](l Lexer[C, T], itemType C, data any) {
+	if e, ok := l.(ValueEmitter[C, T]); ok {
+		e.EmitData(itemType, data)
+		return
+	}
+	l.Emit(itemType)
+}
+
+// emitError pushes an item carrying the error `err` with the lexer's `EmitError()` method if it
+// is a ValueEmitter; or with its `Emit()` method otherwise, without the error
+func emitError[C comparable, T any](l Lexer[C, T], itemType C, err error) {
+	if e, ok := l.(ValueEmitter[C, T]); ok {
+		e.EmitError(itemType, err)
+		return
+	}
+	l.Emit(itemType)
+}
+
+// enter marks the start of a nesting level if the lexer is a Nester, returning false if its
+// MaxDepth limit is exceeded; otherwise the nesting levels are not bound, and it returns true
+func enter[C comparable, T any](l Lexer[C, T]) bool {
+	if n, ok := l.(Nester); ok {
+		return n.Enter()
+	}
+	return true
+}
+
+// leave marks the end of a nesting level if the lexer is a Nester
+func leave[C comparable, T any](l Lexer[C, T]) {
+	if n, ok := l.(Nester); ok {
+		n.Leave()
+	}
+}
+
+// acceptUntil consumes all units up to (but not including) the first unit that passes the
+// `verifFn` validator, returning true; with the lexer's `AcceptUntil()` method if it is an
+// Acceptor
+func acceptUntil[C comparable, T any](l Lexer[C, T], verifFn func(item T) bool) bool {
+	if a, ok := l.(Acceptor[T]); ok {
+		return a.AcceptUntil(verifFn)
+	}
+	pos := l.Pos()
+	for {
+		next := l.Pos()
+		item := l.Next()
+		if l.Pos() == next {
+			// EOF
+			rewind(l, pos)
+			return false
+		}
+		if verifFn(item) {
+			rewind(l, next)
+			return true
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package lex
+
+// Channel identifies a stream of items emitted by a lexer
+//
+// Only the items on the DefaultChannel are returned by the lexer's `NextItem()` method (and seen
+// by a parser); while the items on any other channel (such as comments or whitespace, routed to
+// the HiddenChannel) are kept aside, and can be retrieved with the lexer's `Hidden()` method
+type Channel int
+
+const (
+	// DefaultChannel is the channel for items returned by the lexer's `NextItem()` method
+	DefaultChannel Channel = iota
+	// HiddenChannel is a channel for items that are kept aside from the parser
+	HiddenChannel
+)
+
+// channels keeps track of the per-token-type default channels in a lexer, and of the items
+// emitted on channels other than the DefaultChannel
+type channels[C comparable, T any] struct {
+	routes map[C]Channel
+	hidden []Item[C, T]
+}
+
+// set routes the items of token type `itemType` to the channel `channel` by default
+func (c *channels[C, T]) set(itemType C, channel Channel) {
+	if c.routes == nil {
+		c.routes = make(map[C]Channel)
+	}
+	c.routes[itemType] = channel
+}
+
+// route returns the default channel for the token type `itemType`
+func (c *channels[C, T]) route(itemType C) Channel {
+	return c.routes[itemType]
+}
+
+// truncate discards the hidden items positioned on (or after) the position `pos`
+func (c *channels[C, T]) truncate(pos int) {
+	for idx, item := range c.hidden {
+		if item.Pos >= pos {
+			c.hidden = c.hidden[:idx]
+			return
+		}
+	}
+}
+
+// reset discards the hidden items, keeping the per-token-type default channels
+func (c *channels[C, T]) reset() {
+	c.hidden = nil
+}
+
+// items returns the hidden items, in the order they were emitted; only for the input channels
+// `chs` if any are provided
+func (c *channels[C, T]) items(chs ...Channel) []Item[C, T] {
+	if len(chs) == 0 {
+		return c.hidden
+	}
+
+	items := make([]Item[C, T], 0, len(c.hidden))
+	for _, item := range c.hidden {
+		for _, ch := range chs {
+			if item.Channel == ch {
+				items = append(items, item)
+				break
+			}
+		}
+	}
+	return items
+}
@@ -0,0 +1,112 @@
+package lex_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zalgonoise/gbuf"
+	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+)
+
+// channelState describes a StateFn that emits words and `#` comments, with the whitespace
+// between them emitted on the HiddenChannel
+func channelState[C uint, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	l.AcceptRun(func(item T) bool { return item == ' ' || item == '\n' })
+	if l.Width() > 0 {
+		l.(lex.ChannelEmitter[C, T]).EmitOn(lex.HiddenChannel, (C)(tokenError))
+	}
+
+	switch l.Cur() {
+	case 0:
+		l.Emit((C)(tokenEOF))
+		return nil
+	case '#':
+		l.(lex.Acceptor[T]).AcceptUntil(func(item T) bool { return item == '\n' })
+		if l.Width() == 0 {
+			l.AcceptRun(func(item T) bool { return item != 0 })
+		}
+		l.Emit((C)(tokenComment))
+	default:
+		l.AcceptRun(func(item T) bool { return item != ' ' && item != '\n' && item != 0 })
+		l.Emit((C)(tokenIdent))
+	}
+	return channelState[C, T]
+}
+
+func TestChannels(t *testing.T) {
+	input := []rune("lexing # words\ndata #end")
+
+	l := lex.New(channelState[uint, rune], input)
+	l.Route(tokenComment, lex.HiddenChannel)
+	b := lex.NewBuffer(channelState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(input)))
+	b.Route(tokenComment, lex.HiddenChannel)
+
+	for _, test := range []struct {
+		name   string
+		e      lex.Emitter[uint, rune]
+		hidden func(chs ...lex.Channel) []lex.Item[uint, rune]
+	}{
+		{"Lex", l, l.Hidden},
+		{"LexBuffer", b, b.Hidden},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			items := lex.Collect(test.e)
+			if len(items) != 3 {
+				t.Errorf("token slice length mismatch error: wanted %d ; got %d", 3, len(items))
+				return
+			}
+			if string(items[0].Value) != "lexing" || string(items[1].Value) != "data" || items[1].Pos != 15 {
+				t.Errorf("unexpected items: %v", items)
+			}
+
+			if hidden := test.hidden(); len(hidden) != 5 {
+				t.Errorf("hidden slice length mismatch error: wanted %d ; got %d", 5, len(hidden))
+			}
+			comments := test.hidden(lex.HiddenChannel)
+			if len(comments) != 5 {
+				t.Errorf("hidden slice length mismatch error: wanted %d ; got %d", 5, len(comments))
+				return
+			}
+			var found []lex.Item[uint, rune]
+			for _, item := range comments {
+				if item.Type == tokenComment {
+					found = append(found, item)
+				}
+			}
+			if len(found) != 2 || string(found[0].Value) != "# words" || found[0].Pos != 7 || string(found[1].Value) != "#end" || found[1].Pos != 20 {
+				t.Errorf("unexpected comment items: %v", found)
+			}
+			if none := test.hidden(lex.DefaultChannel); len(none) != 0 {
+				t.Errorf("unexpected items on the default channel: %v", none)
+			}
+		})
+	}
+}
+
+func TestChannelsMaxBuffered(t *testing.T) {
+	input := []rune("a" + strings.Repeat("\n#c", 5000) + "\nb")
+
+	l, err := lex.NewLexBuffer(channelState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(input)), lex.WithLimits(lex.Limits{MaxBuffered: 1000}))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	l.Route(tokenComment, lex.HiddenChannel)
+
+	// the hidden comments and whitespace between both words are not held back in the buffer
+	items := lex.Collect[uint, rune](l)
+	for _, item := range items {
+		if errors.Is(item.Err, lex.ErrMaxBuffered) {
+			t.Errorf("unexpected error: %v", item.Err)
+			return
+		}
+	}
+	if len(items) != 3 || string(items[0].Value) != "a" || string(items[1].Value) != "b" || items[1].Pos != len(input)-1 {
+		t.Errorf("unexpected items: %v", items)
+	}
+	if comments := l.Hidden(lex.HiddenChannel); len(comments) != 10001 {
+		t.Errorf("hidden slice length mismatch error: wanted %d ; got %d", 10001, len(comments))
+	}
+}
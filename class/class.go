@@ -0,0 +1,290 @@
+// Package class provides generic predicates (and character classes) to verify units in a
+// lex.Lexer's `Check()`, `Accept()` and `AcceptRun()` methods
+//
+// Predicates are plain `func(item T) bool` functions, so they plug directly into those methods:
+//
+//	l.AcceptRun(class.Or(class.Word[T](), class.Is[T]('.')))
+//
+// The character classes for byte and rune units are backed by lookup tables, and any predicate
+// can be compiled into one with `Compile()`
+package class
+
+import "unicode"
+
+// Pred is a predicate function, verifying a single unit of type T
+type Pred[T any] func(item T) bool
+
+// Char is a constraint for text units, as bytes or runes
+type Char interface {
+	~byte | ~rune
+}
+
+// Ordered is a constraint for types that support the ordering operators
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// Is returns a Pred matching units equal to `value`
+func Is[T comparable](value T) Pred[T] {
+	return func(item T) bool {
+		return item == value
+	}
+}
+
+// OneOf returns a Pred matching units equal to any of the `values`
+func OneOf[T comparable](values ...T) Pred[T] {
+	if len(values) <= 8 {
+		return func(item T) bool {
+			for _, v := range values {
+				if item == v {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	set := make(map[T]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return func(item T) bool {
+		_, ok := set[item]
+		return ok
+	}
+}
+
+// Range returns a Pred matching units within the range `lo` to `hi` (inclusive)
+func Range[T Ordered](lo, hi T) Pred[T] {
+	return func(item T) bool {
+		return item >= lo && item <= hi
+	}
+}
+
+// Not returns a Pred that negates the Pred `p`
+func Not[T any](p Pred[T]) Pred[T] {
+	return func(item T) bool {
+		return !p(item)
+	}
+}
+
+// And returns a Pred matching units that match all of the Preds `preds`
+func And[T any](preds ...Pred[T]) Pred[T] {
+	return func(item T) bool {
+		for _, p := range preds {
+			if !p(item) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a Pred matching units that match any of the Preds `preds`
+func Or[T any](preds ...Pred[T]) Pred[T] {
+	return func(item T) bool {
+		for _, p := range preds {
+			if p(item) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Compile converts the Pred `p` into a lookup table, for byte or rune units
+//
+// For byte units, `p` is evaluated once for all 256 values. For rune units, `p` is evaluated
+// once for the ASCII range, and called directly for any other rune
+func Compile[T Char](p Pred[T]) Pred[T] {
+	if !wide[T]() {
+		var table [256]bool
+		for i := 0; i < len(table); i++ {
+			table[i] = p((T)(i))
+		}
+		return func(item T) bool {
+			return table[(byte)(item)]
+		}
+	}
+
+	var table [128]bool
+	for i := 0; i < len(table); i++ {
+		table[i] = p((T)(i))
+	}
+	return func(item T) bool {
+		if r := (rune)(item); r >= 0 && r < 128 {
+			return table[r]
+		}
+		return p(item)
+	}
+}
+
+// Chars returns a Pred matching any of the characters in `chars`, backed by a lookup table
+//
+// For byte units, the bytes in `chars` are used; while for rune units its runes are used
+func Chars[T Char](chars string) Pred[T] {
+	if !wide[T]() {
+		var table [256]bool
+		for i := 0; i < len(chars); i++ {
+			table[chars[i]] = true
+		}
+		return func(item T) bool {
+			return table[(byte)(item)]
+		}
+	}
+
+	var table [128]bool
+	var set map[rune]struct{}
+	for _, r := range chars {
+		if r < 128 {
+			table[r] = true
+			continue
+		}
+		if set == nil {
+			set = map[rune]struct{}{}
+		}
+		set[r] = struct{}{}
+	}
+	return func(item T) bool {
+		r := (rune)(item)
+		if r >= 0 && r < 128 {
+			return table[r]
+		}
+		_, ok := set[r]
+		return ok
+	}
+}
+
+// Digit returns a Pred matching ASCII decimal digits
+func Digit[T Char]() Pred[T] {
+	return func(item T) bool {
+		return digitTable.has((rune)(item))
+	}
+}
+
+// HexDigit returns a Pred matching ASCII hexadecimal digits
+func HexDigit[T Char]() Pred[T] {
+	return func(item T) bool {
+		return hexTable.has((rune)(item))
+	}
+}
+
+// OctDigit returns a Pred matching ASCII octal digits
+func OctDigit[T Char]() Pred[T] {
+	return func(item T) bool {
+		return octTable.has((rune)(item))
+	}
+}
+
+// BinDigit returns a Pred matching binary digits
+func BinDigit[T Char]() Pred[T] {
+	return func(item T) bool {
+		return binTable.has((rune)(item))
+	}
+}
+
+// Alpha returns a Pred matching ASCII letters
+func Alpha[T Char]() Pred[T] {
+	return func(item T) bool {
+		return alphaTable.has((rune)(item))
+	}
+}
+
+// Alnum returns a Pred matching ASCII letters and decimal digits
+func Alnum[T Char]() Pred[T] {
+	return func(item T) bool {
+		return alnumTable.has((rune)(item))
+	}
+}
+
+// Word returns a Pred matching ASCII letters, decimal digits and underscores
+func Word[T Char]() Pred[T] {
+	return func(item T) bool {
+		return wordTable.has((rune)(item))
+	}
+}
+
+// Upper returns a Pred matching ASCII upper-case letters
+func Upper[T Char]() Pred[T] {
+	return func(item T) bool {
+		return upperTable.has((rune)(item))
+	}
+}
+
+// Lower returns a Pred matching ASCII lower-case letters
+func Lower[T Char]() Pred[T] {
+	return func(item T) bool {
+		return lowerTable.has((rune)(item))
+	}
+}
+
+// Space returns a Pred matching ASCII whitespace: space, tab, newline, carriage return,
+// vertical tab and form feed
+func Space[T Char]() Pred[T] {
+	return func(item T) bool {
+		return spaceTable.has((rune)(item))
+	}
+}
+
+// Letter returns a Pred matching Unicode letters
+//
+// For byte units, only the ASCII range is considered
+func Letter[T Char]() Pred[T] {
+	return func(item T) bool {
+		r := (rune)(item)
+		if r >= 0 && r < 128 {
+			return alphaTable[r]
+		}
+		return wide[T]() && unicode.IsLetter(r)
+	}
+}
+
+// Number returns a Pred matching Unicode numbers
+//
+// For byte units, only the ASCII range is considered
+func Number[T Char]() Pred[T] {
+	return func(item T) bool {
+		r := (rune)(item)
+		if r >= 0 && r < 128 {
+			return digitTable[r]
+		}
+		return wide[T]() && unicode.IsNumber(r)
+	}
+}
+
+// UnicodeSpace returns a Pred matching Unicode whitespace, as defined by `unicode.IsSpace()`
+//
+// For byte units, only the ASCII range is considered
+func UnicodeSpace[T Char]() Pred[T] {
+	return func(item T) bool {
+		r := (rune)(item)
+		if r >= 0 && r < 128 {
+			return spaceTable[r]
+		}
+		return wide[T]() && unicode.IsSpace(r)
+	}
+}
+
+// In returns a Pred matching runes within any of the Unicode range tables `tables`, such as
+// `unicode.Greek` or `unicode.Punct`
+//
+// It is compiled into a lookup table for the ASCII range; and for byte units, only the ASCII
+// range is considered
+func In[T Char](tables ...*unicode.RangeTable) Pred[T] {
+	if !wide[T]() {
+		return Compile(func(item T) bool {
+			return item < 128 && unicode.In((rune)(item), tables...)
+		})
+	}
+	return Compile(func(item T) bool {
+		return unicode.In((rune)(item), tables...)
+	})
+}
+
+// wide returns true if T is wider than a byte
+func wide[T Char]() bool {
+	var r rune = 0x100
+	return (T)(r) != 0
+}
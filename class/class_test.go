@@ -0,0 +1,94 @@
+package class_test
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/zalgonoise/lex"
+	"github.com/zalgonoise/lex/class"
+)
+
+func TestPredicates(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		pred   class.Pred[rune]
+		match  string
+		reject string
+	}{
+		{"Is", class.Is('a'), "a", "bA"},
+		{"OneOf", class.OneOf('a', 'b', 'c'), "abc", "dA"},
+		{"OneOfSet", class.OneOf([]rune("abcdefghij")...), "aj", "kA"},
+		{"Range", class.Range('a', 'f'), "af", "g`"},
+		{"Not", class.Not(class.Is('a')), "bé", "a"},
+		{"And", class.And(class.Alpha[rune](), class.Not(class.Upper[rune]())), "az", "AZ0"},
+		{"Or", class.Or(class.Digit[rune](), class.Is('_')), "09_", "a-"},
+		{"Chars", class.Chars[rune]("+-é"), "+-é", "*e"},
+		{"Digit", class.Digit[rune](), "0123456789", "a٣"},
+		{"HexDigit", class.HexDigit[rune](), "09afAF", "gG"},
+		{"OctDigit", class.OctDigit[rune](), "07", "89"},
+		{"BinDigit", class.BinDigit[rune](), "01", "2"},
+		{"Word", class.Word[rune](), "aZ0_", "-é "},
+		{"Space", class.Space[rune](), " \t\n\r\v\f", "a "},
+		{"Letter", class.Letter[rune](), "aéλ", "0_"},
+		{"Number", class.Number[rune](), "0٣", "a"},
+		{"UnicodeSpace", class.UnicodeSpace[rune](), "  ", "a"},
+		{"In", class.In[rune](unicode.Greek), "λΩ", "a"},
+		{"Compile", class.Compile(class.Or(class.Letter[rune](), class.Is('_'))), "a_λ", "0 "},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			for _, r := range test.match {
+				if !test.pred(r) {
+					t.Errorf("expected %q to match", r)
+				}
+			}
+			for _, r := range test.reject {
+				if test.pred(r) {
+					t.Errorf("expected %q not to match", r)
+				}
+			}
+		})
+	}
+}
+
+func TestBytePredicates(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		pred   class.Pred[byte]
+		match  string
+		reject string
+	}{
+		{"Chars", class.Chars[byte]("+-"), "+-", "*"},
+		{"Word", class.Word[byte](), "aZ0_", "-\xc3"},
+		{"Letter", class.Letter[byte](), "aZ", "\xc3\xa9"},
+		{"In", class.In[byte](unicode.Latin), "aZ", "\xc3"},
+		{"Compile", class.Compile(class.Or(class.Digit[byte](), class.Is[byte]('.'))), "0.", "a\xff"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			for i := 0; i < len(test.match); i++ {
+				if !test.pred(test.match[i]) {
+					t.Errorf("expected %q to match", test.match[i])
+				}
+			}
+			for i := 0; i < len(test.reject); i++ {
+				if test.pred(test.reject[i]) {
+					t.Errorf("expected %q not to match", test.reject[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLexerPredicates(t *testing.T) {
+	l := lex.New[uint8](nil, []rune("lex_01 data"))
+
+	l.AcceptRun(class.Word[rune]())
+	if l.Pos() != 6 {
+		t.Errorf("unexpected pos value: wanted %d ; got %d", 6, l.Pos())
+	}
+	if !l.Check(class.Space[rune]()) {
+		t.Errorf("expected the current unit to be a space")
+	}
+	if !l.Accept(class.Space[rune]()) || l.Accept(class.Digit[rune]()) {
+		t.Errorf("unexpected acceptance result on position %d", l.Pos())
+	}
+}
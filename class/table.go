@@ -0,0 +1,40 @@
+package class
+
+// ascii is a lookup table for the ASCII range
+type ascii [128]bool
+
+var (
+	digitTable = newASCII(isDigit)
+	hexTable   = newASCII(func(r rune) bool { return isDigit(r) || r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F' })
+	octTable   = newASCII(func(r rune) bool { return r >= '0' && r <= '7' })
+	binTable   = newASCII(func(r rune) bool { return r == '0' || r == '1' })
+	alphaTable = newASCII(isAlpha)
+	alnumTable = newASCII(func(r rune) bool { return isAlpha(r) || isDigit(r) })
+	wordTable  = newASCII(func(r rune) bool { return isAlpha(r) || isDigit(r) || r == '_' })
+	upperTable = newASCII(func(r rune) bool { return r >= 'A' && r <= 'Z' })
+	lowerTable = newASCII(func(r rune) bool { return r >= 'a' && r <= 'z' })
+	spaceTable = newASCII(func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\v' || r == '\f'
+	})
+)
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isAlpha(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+func newASCII(fn func(r rune) bool) *ascii {
+	var table = new(ascii)
+	for r := range table {
+		table[r] = fn((rune)(r))
+	}
+	return table
+}
+
+// has returns true if the rune `r` is in the ASCII range and set in the table
+func (a *ascii) has(r rune) bool {
+	return r >= 0 && r < 128 && a[r]
+}
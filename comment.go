@@ -0,0 +1,144 @@
+package lex
+
+import (
+	"errors"
+
+	"github.com/zalgonoise/lex/class"
+)
+
+// ErrUnterminatedComment is a preset error for block comments missing their closing delimiter,
+// before the end of the input
+var ErrUnterminatedComment = errors.New("unterminated block comment")
+
+// CommentTokens describes the token types emitted by a Comment
+type CommentTokens[C comparable] struct {
+	// Comment is the token type for line and block comments, including their delimiters
+	Comment C
+	// Error is the token type for the error items, on unterminated block comments
+	Error C
+}
+
+// Comment scans line and block comments in byte or rune lexers, with configurable delimiters;
+// as a fragment to be called from a StateFn, with its `Scan()` method
+//
+// By default, it scans C-style comments (`// line` and `/* block */`), emitting them as Comment
+// items; which can be routed to the HiddenChannel with the lexer's `Route()` method. If set
+// to ignore them (with the `Ignore()` method), comments are skipped like whitespace, being kept
+// as trivia according to the lexer's TriviaMode
+type Comment[C comparable, T class.Char] struct {
+	tokens     CommentTokens[C]
+	line       [][]T
+	blockOpen  []T
+	blockClose []T
+	nested     bool
+	ignore     bool
+}
+
+// NewComment creates a Comment emitting the token types in `tokens`, for C-style comments
+func NewComment[C comparable, T class.Char](tokens CommentTokens[C]) *Comment[C, T] {
+	return &Comment[C, T]{
+		tokens:     tokens,
+		line:       [][]T{{'/', '/'}},
+		blockOpen:  []T{'/', '*'},
+		blockClose: []T{'*', '/'},
+	}
+}
+
+// Line sets the opening delimiters for line comments (like `//`, `#` or `--`), which span up
+// to (but not including) the end of the line. Calling it with no delimiters disables line
+// comments
+func (c *Comment[C, T]) Line(delims ...[]T) {
+	c.line = delims
+}
+
+// Block sets the opening and closing delimiters for block comments (like `/*` and `*/`), which
+// can span multiple lines. Calling it with an empty opening delimiter disables block comments
+//
+// Block comments take precedence over line comments, so that a block delimiter can extend a
+// line delimiter (like Lua's `--[[` and `--`)
+func (c *Comment[C, T]) Block(open, close []T) {
+	c.blockOpen = open
+	c.blockClose = close
+}
+
+// Nested sets whether block comments can be nested, where each opening delimiter within a block
+// comment must be matched by its own closing delimiter
+//
+// Each nesting level is entered as a nesting level in the lexer (with its `Enter()` method),
+// counting towards its MaxDepth limit; if the lexer is a Nester
+func (c *Comment[C, T]) Nested(enabled bool) {
+	c.nested = enabled
+}
+
+// Ignore sets whether comments are skipped with the lexer's `Ignore()` method, instead of
+// emitted as Comment items
+func (c *Comment[C, T]) Ignore(enabled bool) {
+	c.ignore = enabled
+}
+
+// Scan lexes the comment on the lexer's cursor, emitting it as a Comment item (or ignoring it)
+// and returns true. The lexer's starting point must be on its cursor (with no units pending to
+// be emitted or ignored)
+//
+// Unterminated block comments are emitted as an Error item wrapping ErrUnterminatedComment,
+// positioned on their opening delimiter and spanning up to the end of the input, even if set to
+// ignore comments
+//
+// If the cursor is not on a comment's opening delimiter, nothing is consumed and false is
+// returned
+func (c *Comment[C, T]) Scan(l Lexer[C, T]) bool {
+	if len(c.blockOpen) > 0 && AcceptSeq(l, c.blockOpen) {
+		return c.block(l)
+	}
+	for _, delim := range c.line {
+		if len(delim) > 0 && AcceptSeq(l, delim) {
+			for l.Cur() != '\n' && !atEOF(l) {
+				l.Next()
+			}
+			c.emit(l)
+			return true
+		}
+	}
+	return false
+}
+
+// block consumes the block comment following its opening delimiter, up to its closing delimiter
+func (c *Comment[C, T]) block(l Lexer[C, T]) bool {
+	depth := 1
+	if c.nested && !enter(l) {
+		return true
+	}
+	for depth > 0 {
+		switch {
+		case AcceptSeq(l, c.blockClose):
+			depth--
+			if c.nested {
+				leave(l)
+			}
+		case c.nested && AcceptSeq(l, c.blockOpen):
+			depth++
+			if !enter(l) {
+				return true
+			}
+		case atEOF(l):
+			for ; c.nested && depth > 0; depth-- {
+				leave(l)
+			}
+			emitError(l, c.tokens.Error, ErrUnterminatedComment)
+			return true
+		default:
+			l.Next()
+		}
+	}
+	c.emit(l)
+	return true
+}
+
+// emit emits or ignores the scanned comment, as set with the `Ignore()` method
+func (c *Comment[C, T]) emit(l Lexer[C, T]) {
+	if c.ignore {
+		l.Ignore()
+		return
+	}
+	l.Emit(c.tokens.Comment)
+}
@@ -0,0 +1,189 @@
+package lex_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zalgonoise/gbuf"
+	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+)
+
+var commentTokens = lex.CommentTokens[uint]{
+	Comment: tokenComment,
+	Error:   tokenError,
+}
+
+// commentSetup configures a Comment over runes or bytes, with string delimiters
+type commentSetup struct {
+	line   []string
+	open   string
+	close  string
+	nested bool
+}
+
+func newComment[T rune | byte](setup *commentSetup) *lex.Comment[uint, T] {
+	c := lex.NewComment[uint, T](commentTokens)
+	if setup == nil {
+		return c
+	}
+	delims := make([][]T, 0, len(setup.line))
+	for _, delim := range setup.line {
+		delims = append(delims, units[T](delim))
+	}
+	c.Line(delims...)
+	c.Block(units[T](setup.open), units[T](setup.close))
+	c.Nested(setup.nested)
+	return c
+}
+
+// units converts the ASCII string `s` into a slice of units
+func units[T rune | byte](s string) []T {
+	out := make([]T, len(s))
+	for idx := range s {
+		out[idx] = T(s[idx])
+	}
+	return out
+}
+
+func TestComment(t *testing.T) {
+	type want struct {
+		pos   int
+		token uint
+		value string
+		err   error
+	}
+
+	for _, test := range []struct {
+		name  string
+		setup *commentSetup
+		input string
+		wants []want
+	}{
+		{
+			name:  "Line",
+			input: "a // note\nb",
+			wants: []want{{0, tokenIdent, "a", nil}, {2, tokenComment, "// note", nil}, {10, tokenIdent, "b", nil}},
+		},
+		{
+			name:  "LineEOF",
+			input: "a //",
+			wants: []want{{0, tokenIdent, "a", nil}, {2, tokenComment, "//", nil}},
+		},
+		{
+			name:  "Block",
+			input: "a /* x\n * y */ b",
+			wants: []want{{0, tokenIdent, "a", nil}, {2, tokenComment, "/* x\n * y */", nil}, {15, tokenIdent, "b", nil}},
+		},
+		{
+			name:  "NotNested",
+			input: "/* a /* b */ c */",
+			wants: []want{{0, tokenComment, "/* a /* b */", nil}, {13, tokenIdent, "c", nil}, {15, tokenPeriod, "*", nil}, {16, tokenPeriod, "/", nil}},
+		},
+		{
+			name:  "Nested",
+			setup: &commentSetup{line: []string{"//"}, open: "/*", close: "*/", nested: true},
+			input: "/* a /* b */ c */ d",
+			wants: []want{{0, tokenComment, "/* a /* b */ c */", nil}, {18, tokenIdent, "d", nil}},
+		},
+		{
+			name:  "Unterminated",
+			input: "a /* b",
+			wants: []want{{0, tokenIdent, "a", nil}, {2, tokenError, "/* b", lex.ErrUnterminatedComment}},
+		},
+		{
+			name:  "UnterminatedNested",
+			setup: &commentSetup{open: "/*", close: "*/", nested: true},
+			input: "/* a /* b */",
+			wants: []want{{0, tokenError, "/* a /* b */", lex.ErrUnterminatedComment}},
+		},
+		{
+			name:  "Delimiters",
+			setup: &commentSetup{line: []string{"#", "--"}},
+			input: "a # b\n-- c\n/ d",
+			wants: []want{{0, tokenIdent, "a", nil}, {2, tokenComment, "# b", nil}, {6, tokenComment, "-- c", nil}, {11, tokenPeriod, "/", nil}, {13, tokenIdent, "d", nil}},
+		},
+		{
+			name:  "BlockPrecedence",
+			setup: &commentSetup{line: []string{"--"}, open: "--[[", close: "]]"},
+			input: "--[[ a\n]] -- b",
+			wants: []want{{0, tokenComment, "--[[ a\n]]", nil}, {10, tokenComment, "-- b", nil}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			verify := func(t *testing.T, items []lex.Item[uint, rune]) {
+				// drop the EOF item
+				items = items[:len(items)-1]
+				if len(items) != len(test.wants) {
+					t.Errorf("unexpected items: wanted %d ; got %d: %v", len(test.wants), len(items), items)
+					return
+				}
+				for idx, want := range test.wants {
+					item := items[idx]
+					if item.Pos != want.pos || item.Type != want.token || string(item.Value) != want.value {
+						t.Errorf("unexpected item #%d: wanted %d %q on position %d ; got %v", idx, want.token, want.value, want.pos, item)
+					}
+					if !errors.Is(item.Err, want.err) || (want.err == nil) != (item.Err == nil) {
+						t.Errorf("unexpected error #%d: wanted %v ; got %v", idx, want.err, item.Err)
+					}
+				}
+			}
+
+			input := []rune(test.input)
+			state := scanState(newComment[rune](test.setup).Scan)
+
+			t.Run("Lex", func(t *testing.T) {
+				verify(t, lex.Collect[uint, rune](lex.New(state, input)))
+			})
+			t.Run("LexBuffer", func(t *testing.T) {
+				verify(t, lex.Collect[uint, rune](lex.NewBuffer(state, (gio.Reader[rune])(gbuf.NewReader(input)))))
+			})
+			t.Run("Bytes", func(t *testing.T) {
+				items := lex.Collect[uint, byte](lex.New(scanState(newComment[byte](test.setup).Scan), []byte(test.input)))
+				runeItems := make([]lex.Item[uint, rune], 0, len(items))
+				for _, item := range items {
+					runeItems = append(runeItems, lex.Item[uint, rune]{Pos: item.Pos, Type: item.Type, Value: []rune(string(item.Value)), Err: item.Err})
+				}
+				verify(t, runeItems)
+			})
+		})
+	}
+}
+
+func TestCommentTrivia(t *testing.T) {
+	input := "a /* b */ c // d\n"
+
+	c := lex.NewComment[uint, rune](commentTokens)
+	c.Ignore(true)
+	l := lex.New(scanState(c.Scan), []rune(input))
+	l.Trivia(lex.TriviaLeading)
+
+	items := lex.Collect[uint, rune](l)
+	if len(items) != 3 {
+		t.Errorf("unexpected items: wanted %d ; got %d: %v", 3, len(items), items)
+		return
+	}
+	for idx, leading := range []string{"", " /* b */ ", " // d\n"} {
+		if string(items[idx].Leading) != leading {
+			t.Errorf("unexpected leading trivia #%d: wanted %q ; got %q", idx, leading, string(items[idx].Leading))
+		}
+	}
+	if output := string(lex.Reconstruct(items)); output != input {
+		t.Errorf("unexpected reconstructed input: wanted %q ; got %q", input, output)
+	}
+}
+
+func TestCommentMaxDepth(t *testing.T) {
+	c := lex.NewComment[uint, rune](commentTokens)
+	c.Nested(true)
+	l, err := lex.NewLex(scanState(c.Scan), []rune("/* /* /* a */ */ */"), lex.WithLimits(lex.Limits{MaxDepth: 2}))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	items := lex.Collect[uint, rune](l)
+	if last := items[len(items)-1]; !errors.Is(last.Err, lex.ErrMaxDepth) {
+		t.Errorf("unexpected EOF item: wanted error %v ; got %v", lex.ErrMaxDepth, last)
+	}
+}
@@ -0,0 +1,116 @@
+package lex_test
+
+import (
+	"testing"
+
+	"github.com/zalgonoise/gbuf"
+	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+	"github.com/zalgonoise/lex/lextest"
+)
+
+// synthState describes a StateFn that emits words and quoted values (without the quotes); with
+// a zero-width period item on each newline, and a separate item for exclamation marks
+func synthState[C uint, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	l.AcceptRun(func(item T) bool { return item == ' ' })
+	l.Ignore()
+
+	switch l.Cur() {
+	case 0:
+		l.Emit((C)(tokenEOF))
+		return nil
+	case '\n':
+		l.(lex.RangeEmitter[C, T]).EmitEmpty((C)(tokenPeriod))
+		l.Next()
+		l.Ignore()
+	case '"':
+		l.Next()
+		l.(lex.Acceptor[T]).AcceptUntil(func(item T) bool { return item == '"' })
+		value := make([]T, l.Width()-1)
+		copy(value, l.Extract(l.Start()+1, l.Pos()))
+		l.Next()
+		l.(lex.ValueEmitter[C, T]).EmitValue((C)(tokenIdent), value)
+	default:
+		l.AcceptRun(func(item T) bool { return item >= 'a' && item <= 'z' })
+		if l.Cur() == '!' {
+			l.(lex.RangeEmitter[C, T]).EmitRange((C)(tokenComment), l.Pos(), l.Pos()+1)
+			l.Emit((C)(tokenIdent))
+			l.Next()
+			l.Ignore()
+			break
+		}
+		l.Emit((C)(tokenIdent))
+	}
+	return synthState[C, T]
+}
+
+func TestSyntheticEmit(t *testing.T) {
+	input := []rune("lex \"da ta\"\nfoo!")
+	wants := []lex.Item[uint, rune]{
+		lex.NewItem(0, tokenIdent, []rune("lex")...),
+		lex.NewItem(4, tokenIdent, []rune("da ta")...),
+		lex.NewItem[uint, rune](11, tokenPeriod),
+		lex.NewItem(15, tokenComment, '!'),
+		lex.NewItem(12, tokenIdent, []rune("foo")...),
+		lex.NewItem[uint, rune](16, tokenEOF),
+	}
+
+	t.Run("Lex", func(t *testing.T) {
+		lextest.Compare(t, wants, lex.Collect[uint, rune](lex.New(synthState[uint, rune], input)))
+	})
+	t.Run("LexBuffer", func(t *testing.T) {
+		lextest.Compare(t, wants, lex.Collect[uint, rune](
+			lex.NewBuffer(synthState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(input))),
+		))
+	})
+}
+
+// dataState describes a StateFn that emits digit runs as ident items holding their parsed value,
+// and any other units as period items
+func dataState[C uint, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	switch {
+	case l.Cur() == 0:
+		l.Emit((C)(tokenEOF))
+		return nil
+	case l.Accept(func(item T) bool { return item >= '0' && item <= '9' }):
+		l.AcceptRun(func(item T) bool { return item >= '0' && item <= '9' })
+		var n int64
+		for _, unit := range l.Extract(l.Start(), l.Pos()) {
+			n = n*10 + int64(unit-'0')
+		}
+		l.(lex.ValueEmitter[C, T]).EmitData((C)(tokenIdent), n)
+	default:
+		l.Next()
+		l.Emit((C)(tokenPeriod))
+	}
+	return dataState[C, T]
+}
+
+func TestEmitData(t *testing.T) {
+	input := []rune("42.7")
+	wants := []lex.Item[uint, rune]{
+		{Pos: 0, Type: tokenIdent, Value: []rune("42"), Data: int64(42)},
+		lex.NewItem(2, tokenPeriod, '.'),
+		{Pos: 3, Type: tokenIdent, Value: []rune("7"), Data: int64(7)},
+		lex.NewItem[uint, rune](4, tokenEOF),
+	}
+
+	items := lex.Collect[uint, rune](lex.New(dataState[uint, rune], input))
+	lextest.Compare(t, wants, items)
+	lextest.Compare(t, wants, lex.Collect[uint, rune](
+		lex.NewBuffer(dataState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(input))),
+	))
+
+	if n, ok := lex.Data[int64](items[0]); !ok || n != 42 {
+		t.Errorf("unexpected data: wanted %d ; got %v (%v)", 42, n, ok)
+	}
+	if _, ok := lex.Data[string](items[0]); ok {
+		t.Errorf("expected data of a different type not to be returned")
+	}
+	if _, ok := lex.Data[int64](items[1]); ok {
+		t.Errorf("expected an item without data not to return any")
+	}
+	if lex.Equal(items[0], lex.NewItem(0, tokenIdent, []rune("42")...)) {
+		t.Errorf("expected items with different data to differ")
+	}
+}
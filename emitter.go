@@ -0,0 +1,73 @@
+package lex
+
+// EmitterFunc is an adapter to allow the use of ordinary functions as Emitters
+type EmitterFunc[C comparable, T any] func() Item[C, T]
+
+// NextItem implements the Emitter interface, by calling the EmitterFunc
+func (fn EmitterFunc[C, T]) NextItem() Item[C, T] {
+	return fn()
+}
+
+// Filter wraps the Emitter `e`, only returning the items for which the input `keep` function
+// returns true
+//
+// EOF items (with a zero-value token type) are always returned, regardless of `keep`
+func Filter[C comparable, T any](e Emitter[C, T], keep func(item Item[C, T]) bool) Emitter[C, T] {
+	var eof C
+	return EmitterFunc[C, T](func() Item[C, T] {
+		for {
+			item := e.NextItem()
+			if item.Type == eof || keep(item) {
+				return item
+			}
+		}
+	})
+}
+
+// Map wraps the Emitter `e`, passing each item through the input `fn` function before
+// returning it
+//
+// EOF items (with a zero-value token type) are returned as-is, without calling `fn`
+func Map[C comparable, T any](e Emitter[C, T], fn func(item Item[C, T]) Item[C, T]) Emitter[C, T] {
+	var eof C
+	return EmitterFunc[C, T](func() Item[C, T] {
+		item := e.NextItem()
+		if item.Type == eof {
+			return item
+		}
+		return fn(item)
+	})
+}
+
+// Tee wraps the Emitter `e`, calling the input `fn` function with each item (including EOF
+// items) before returning it, unchanged
+func Tee[C comparable, T any](e Emitter[C, T], fn func(item Item[C, T])) Emitter[C, T] {
+	return EmitterFunc[C, T](func() Item[C, T] {
+		item := e.NextItem()
+		fn(item)
+		return item
+	})
+}
+
+// Concat joins the input Emitters `emitters` into a single Emitter, returning the items of
+// each Emitter in order
+//
+// The EOF items from all Emitters except the last one are discarded; and the items' positions
+// are kept as returned by their Emitter
+func Concat[C comparable, T any](emitters ...Emitter[C, T]) Emitter[C, T] {
+	var eof C
+	var idx int
+	return EmitterFunc[C, T](func() Item[C, T] {
+		for {
+			if idx >= len(emitters) {
+				return Item[C, T]{}
+			}
+			item := emitters[idx].NextItem()
+			if item.Type == eof && idx < len(emitters)-1 {
+				idx++
+				continue
+			}
+			return item
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package lex_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zalgonoise/lex"
+)
+
+func TestEmitterMiddleware(t *testing.T) {
+	// input: `lexing.data.`
+	t.Run("Filter", func(t *testing.T) {
+		e := lex.Filter[uint, rune](lex.New(initState[uint, rune], testInput2), func(item lex.Item[uint, rune]) bool {
+			return item.Type != tokenPeriod
+		})
+		items := lex.Collect(e)
+		if len(items) != 3 {
+			t.Errorf("token slice length mismatch error: wanted %d ; got %d", 3, len(items))
+			return
+		}
+		if items[1].Type != tokenIdent || string(items[1].Value) != "data" {
+			t.Errorf("unexpected item: wanted ident `data` ; got %d `%s`", items[1].Type, string(items[1].Value))
+		}
+		if items[2].Type != tokenEOF {
+			t.Errorf("unexpected token type: wanted %d ; got %d", tokenEOF, items[2].Type)
+		}
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		e := lex.Map[uint, rune](lex.New(initState[uint, rune], testInput2), func(item lex.Item[uint, rune]) lex.Item[uint, rune] {
+			if item.Type == tokenIdent {
+				item.Type = tokenError
+			}
+			return item
+		})
+		items := lex.Collect(e)
+		if len(items) != 5 {
+			t.Errorf("token slice length mismatch error: wanted %d ; got %d", 5, len(items))
+			return
+		}
+		if items[0].Type != tokenError || items[2].Type != tokenError {
+			t.Errorf("unexpected token types: wanted %d ; got %d and %d", tokenError, items[0].Type, items[2].Type)
+		}
+	})
+
+	t.Run("Tee", func(t *testing.T) {
+		var sb = new(strings.Builder)
+		e := lex.Tee[uint, rune](lex.New(initState[uint, rune], testInput2), func(item lex.Item[uint, rune]) {
+			sb.WriteString(string(item.Value))
+		})
+		if items := lex.Collect(e); len(items) != 5 {
+			t.Errorf("token slice length mismatch error: wanted %d ; got %d", 5, len(items))
+		}
+		if sb.String() != "lexing.data." {
+			t.Errorf("unexpected output: wanted %s ; got %s", "lexing.data.", sb.String())
+		}
+	})
+
+	t.Run("Concat", func(t *testing.T) {
+		e := lex.Concat[uint, rune](
+			lex.New(initState[uint, rune], testInput2),
+			lex.New(initState[uint, rune], testInput1),
+		)
+		items := lex.Collect(e)
+		if len(items) != 7 {
+			t.Errorf("token slice length mismatch error: wanted %d ; got %d", 7, len(items))
+			return
+		}
+		if string(items[4].Value) != "lexing data" {
+			t.Errorf("unexpected output value: wanted `%s` ; got `%s`", "lexing data", string(items[4].Value))
+		}
+		if i := e.NextItem(); i.Type != tokenEOF {
+			t.Errorf("unexpected token type: wanted %d ; got %d", tokenEOF, i.Type)
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package protofile
+
+import (
+	"testing"
+
+	"github.com/zalgonoise/lex/lextest"
+)
+
+func FuzzLexer(f *testing.F) {
+	f.Add(protofile)
+	f.Add([]byte(`syntax = "proto3";`))
+	f.Add([]byte(`enum Status { ok = 1; }`))
+
+	lextest.Fuzz(f, newScanners[ProtoToken, byte]().initState)
+}
@@ -33,6 +33,8 @@ const (
 	TokenFLOAT
 	TokenSTRING
 	TokenBYTES
+
+	TokenERROR
 )
 
 var keywords = map[string]ProtoToken{
@@ -1,54 +1,105 @@
 package protofile
 
 import (
-	"bytes"
-
 	"github.com/zalgonoise/lex"
+	"github.com/zalgonoise/lex/class"
 )
 
-var chars = []byte("0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ_")
+// scanners holds the keywords table and the scanners that the lexer's StateFns call, built once
+// for each lexer
+type scanners[C ProtoToken, T byte] struct {
+	keywords *lex.Keywords[C, T]
+	number   *lex.Number[C, T]
+	quoted   *lex.Quoted[C, T]
+	comment  *lex.Comment[C, T]
+}
+
+func newScanners[C ProtoToken, T byte]() *scanners[C, T] {
+	table := make(map[string]C, len(keywords))
+	for word, token := range keywords {
+		table[word] = (C)(token)
+	}
+
+	s := &scanners[C, T]{
+		keywords: lex.NewKeywords[C, T]((C)(TokenIDENT), table),
+		number: lex.NewNumber[C, T](lex.NumberTokens[C]{
+			Int:   (C)(TokenVALUE),
+			Float: (C)(TokenVALUE),
+			Error: (C)(TokenERROR),
+		}, lex.NumberDefault),
+		quoted: lex.NewQuoted[C, T](lex.QuotedTokens[C]{
+			String: (C)(TokenVALUE),
+			Error:  (C)(TokenERROR),
+		}, lex.EscapeC),
+		comment: lex.NewComment[C, T](lex.CommentTokens[C]{
+			Error: (C)(TokenERROR),
+		}),
+	}
+	s.quoted.Quotes('"', '\'')
+	s.comment.Ignore(true)
+
+	return s
+}
+
+func (s *scanners[C, T]) initState(l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	switch cur := l.Cur(); {
+	case cur >= '0' && cur <= '9':
+		return s.stateNumber
+	case cur == '"' || cur == '\'':
+		return s.stateString
+	case cur == '/':
+		return s.stateComment
+	}
 
-func initState[C ProtoToken, T byte](l lex.Lexer[C, T]) lex.StateFn[C, T] {
 	switch l.Next() {
 	case '=':
 		l.Emit((C)(TokenEQUAL))
-		return initState[C, T]
-	case '"':
-		l.Emit((C)(TokenDQUOTE))
-		return initState[C, T]
+		return s.initState
 	case ';':
 		l.Emit((C)(TokenSEMICOL))
-		return initState[C, T]
+		return s.initState
 	case '{':
 		l.Emit((C)(TokenLBRACE))
-		return initState[C, T]
+		return s.initState
 	case '}':
 		l.Emit((C)(TokenRBRACE))
-		return initState[C, T]
+		return s.initState
 	case ' ', '\t', '\n':
 		l.Ignore()
-		return initState[C, T]
+		return s.initState
 	case 0:
 		return nil
 	default:
-		return stateIDENT[C, T]
+		return s.stateIDENT
 	}
 }
 
-func stateIDENT[C ProtoToken, T byte](l lex.Lexer[C, T]) lex.StateFn[C, T] {
-	l.AcceptRun(func(item T) bool {
-		return bytes.IndexByte(chars, byte(item)) >= 0
-	})
+func (s *scanners[C, T]) stateIDENT(l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	l.AcceptRun(class.Word[T]())
 	if l.Width() > 0 {
-		buf := l.Extract(l.Start(), l.Pos())
-
-		tok, ok := keywords[toString(buf)]
-		if ok {
-			l.Emit((C)(tok))
-			return initState[C, T]
-		}
-		l.Emit((C)(TokenIDENT))
+		s.keywords.Emit(l)
+	}
+
+	return s.initState
+}
+
+func (s *scanners[C, T]) stateNumber(l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	s.number.Scan(l)
+
+	return s.initState
+}
+
+func (s *scanners[C, T]) stateString(l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	s.quoted.Scan(l)
+
+	return s.initState
+}
+
+func (s *scanners[C, T]) stateComment(l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	if !s.comment.Scan(l) {
+		l.Next()
+		return s.stateIDENT
 	}
 
-	return initState[C, T]
+	return s.initState
 }
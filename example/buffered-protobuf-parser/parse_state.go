@@ -16,6 +16,8 @@ func initParse[C ProtoToken, T byte](t *parse.Tree[C, T]) parse.ParseFn[C, T] {
 			return parseEnum[C, T]
 		case (C)(TokenMESSAGE):
 			return parseMessage[C, T]
+		case (C)(TokenERROR):
+			return parseError[C, T]
 		default:
 			return nil
 		}
@@ -23,53 +25,16 @@ func initParse[C ProtoToken, T byte](t *parse.Tree[C, T]) parse.ParseFn[C, T] {
 	return nil
 }
 
-// func parseText[C ProtoToken, T byte](t *parse.Tree[C, T]) parse.ParseFn[C, T] {
-// 	item := t.Next()
-// 	if tok, ok := keywords[toString(item.Value)]; ok {
-// 		item.Type = (C)(tok)
-
-// 		t.Node(item)
-// 		switch tok {
-// 		case TokenSYNTAX:
-// 			return parseSyntax[C, T]
-// 		case TokenPACKAGE:
-// 			return parsePackage[C, T]
-// 		case TokenENUM:
-// 			return parseEnum[C, T]
-// 		case TokenMESSAGE:
-// 			return parseMessage[C, T]
-// 		default:
-// 			return initParse[C, T]
-// 		}
-// 	}
-
-//		t.Node(item)
-//		return initParse[C, T]
-//	}
-
-func toString[T byte](v []T) string {
-	buf := make([]byte, len(v))
-	for i, b := range v {
-		buf[i] = (byte)(b)
-	}
-	return (string)(buf)
-}
-
 func parseSyntax[C ProtoToken, T byte](t *parse.Tree[C, T]) parse.ParseFn[C, T] {
 	t.Node(t.Next())
 	if t.Peek().Type == C(TokenEQUAL) {
 		t.Next()
 	}
-	if t.Peek().Type == C(TokenDQUOTE) {
-		t.Next()
-	}
-	if t.Peek().Type == (C)(TokenIDENT) {
-		item := t.Next()
-		item.Type = C(TokenVALUE)
-		t.Node(item)
+	if t.Peek().Type == (C)(TokenERROR) {
+		return parseError[C, T]
 	}
-	if t.Peek().Type == C(TokenDQUOTE) {
-		t.Next()
+	if t.Peek().Type == (C)(TokenVALUE) {
+		t.Node(t.Next())
 	}
 	if t.Peek().Type == (C)(TokenSEMICOL) {
 		t.Next() // skip
@@ -97,7 +62,7 @@ func parseEnum[C ProtoToken, T byte](t *parse.Tree[C, T]) parse.ParseFn[C, T] {
 	if t.Peek().Type == (C)(TokenIDENT) {
 		item := t.Next()
 		item.Type = (C)(TokenTYPE)
-		types[toString(item.Value)] = struct{}{}
+		types[lex.Text(item)] = struct{}{}
 		t.Node(item)
 		if t.Peek().Type == (C)(TokenLBRACE) {
 			t.Next() // skip
@@ -117,7 +82,10 @@ func parseKeyValue[C ProtoToken, T byte](t *parse.Tree[C, T]) parse.ParseFn[C, T
 		if t.Peek().Type == (C)(TokenEQUAL) {
 			t.Next()
 		}
-		if t.Peek().Type == (C)(TokenIDENT) {
+		if t.Peek().Type == (C)(TokenERROR) {
+			return parseError[C, T]
+		}
+		if typ := t.Peek().Type; typ == (C)(TokenIDENT) || typ == (C)(TokenVALUE) {
 			item := t.Next()
 			item.Type = (C)(TokenVALUE)
 			t.Node(item)
@@ -140,7 +108,7 @@ func parseMessage[C ProtoToken, T byte](t *parse.Tree[C, T]) parse.ParseFn[C, T]
 	if t.Peek().Type == (C)(TokenIDENT) {
 		item := t.Next()
 		item.Type = (C)(TokenTYPE)
-		types[toString(item.Value)] = struct{}{}
+		types[lex.Text(item)] = struct{}{}
 		t.Node(item)
 		if t.Peek().Type == (C)(TokenLBRACE) {
 			t.Next() // skip
@@ -167,7 +135,7 @@ func parseTypeKeyValue[C ProtoToken, T byte](t *parse.Tree[C, T]) parse.ParseFn[
 
 		if t.Peek().Type == (C)(TokenIDENT) {
 			item := t.Next()
-			_, ok := types[toString(item.Value)]
+			_, ok := types[lex.Text(item)]
 			if ok {
 				item.Type = (C)(TokenTYPE)
 				elems[0] = item
@@ -179,7 +147,10 @@ func parseTypeKeyValue[C ProtoToken, T byte](t *parse.Tree[C, T]) parse.ParseFn[
 		if t.Peek().Type == (C)(TokenEQUAL) {
 			t.Next()
 		}
-		if t.Peek().Type == (C)(TokenIDENT) {
+		if t.Peek().Type == (C)(TokenERROR) {
+			return parseError[C, T]
+		}
+		if typ := t.Peek().Type; typ == (C)(TokenIDENT) || typ == (C)(TokenVALUE) {
 			item := t.Next()
 			item.Type = (C)(TokenVALUE)
 			t.Node(item)
@@ -207,3 +178,12 @@ func parseTypeKeyValue[C ProtoToken, T byte](t *parse.Tree[C, T]) parse.ParseFn[
 	t.Set(t.Parent().Parent)
 	return initParse[C, T]
 }
+
+// parseError adds the error item as a top-level node, ending the parsing
+func parseError[C ProtoToken, T byte](t *parse.Tree[C, T]) parse.ParseFn[C, T] {
+	for t.Cur().Parent != nil {
+		t.Set(t.Cur().Parent)
+	}
+	t.Node(t.Next())
+	return nil
+}
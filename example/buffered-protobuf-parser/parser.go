@@ -25,8 +25,10 @@ func processFn[C ProtoToken, T byte, R string](t *parse.Tree[C, T]) (R, error) {
 			processEnum(sb, n)
 		case C(TokenMESSAGE):
 			processMessage(sb, n, 0)
+		case C(TokenERROR):
+			return (R)(sb.String()), fmt.Errorf("lex error on position %d: %w", n.Pos, n.Err)
 		default:
-			return (R)(sb.String()), fmt.Errorf("invalid top-level token: %d -- %s", n.Type, toString(n.Value))
+			return (R)(sb.String()), fmt.Errorf("invalid top-level token: %d -- %s", n.Type, lex.Text(n.Item))
 		}
 	}
 
@@ -37,7 +39,8 @@ func processSyntax[C ProtoToken, T byte](sb *strings.Builder, n *parse.Node[C, T
 	sb.WriteString("syntax: ")
 	for _, e := range n.Edges {
 		if e.Type == C(TokenVALUE) {
-			sb.WriteString(toString(e.Value))
+			value, _ := lex.Data[string](e.Item)
+			sb.WriteString(value)
 		}
 	}
 	sb.WriteByte('\n')
@@ -47,7 +50,7 @@ func processPackage[C ProtoToken, T byte](sb *strings.Builder, n *parse.Node[C,
 	sb.WriteString("package: ")
 	for _, e := range n.Edges {
 		if e.Type == C(TokenVALUE) {
-			sb.WriteString(toString(e.Value))
+			sb.WriteString(lex.Text(e.Item))
 		}
 	}
 	sb.WriteByte('\n')
@@ -62,7 +65,7 @@ func processEnum[C ProtoToken, T byte](sb *strings.Builder, n *parse.Node[C, T])
 	for _, e := range n.Edges {
 		if e.Type == (C)(TokenTYPE) {
 			sb.WriteString("type: ")
-			sb.WriteString(toString(e.Value))
+			sb.WriteString(lex.Text(e.Item))
 			sb.WriteByte('\n')
 			for _, ee := range e.Edges {
 				processEnumFields(sb, ee)
@@ -73,10 +76,10 @@ func processEnum[C ProtoToken, T byte](sb *strings.Builder, n *parse.Node[C, T])
 func processEnumFields[C ProtoToken, T byte](sb *strings.Builder, n *parse.Node[C, T]) {
 	if n.Type == (C)(TokenVALUE) {
 		sb.WriteString("\tid: ")
-		sb.WriteString(toString(n.Value))
+		sb.WriteString(lex.Text(n.Item))
 		for _, e := range n.Edges {
 			sb.WriteString("\tname: ")
-			sb.WriteString(toString(e.Value))
+			sb.WriteString(lex.Text(e.Item))
 		}
 		sb.WriteByte('\n')
 	}
@@ -94,7 +97,7 @@ func processMessage[C ProtoToken, T byte](sb *strings.Builder, n *parse.Node[C,
 		if e.Type == (C)(TokenTYPE) {
 			addIdent(sb, ident)
 			sb.WriteString("type: ")
-			sb.WriteString(toString(e.Value))
+			sb.WriteString(lex.Text(e.Item))
 			sb.WriteByte('\n')
 
 			for _, ee := range e.Edges {
@@ -115,17 +118,17 @@ func processMessageFields[C ProtoToken, T byte](sb *strings.Builder, n *parse.No
 	case (C)(TokenVALUE):
 		addIdent(sb, ident)
 		sb.WriteString("\tid: ")
-		sb.WriteString(toString(n.Value))
+		sb.WriteString(lex.Text(n.Item))
 		for _, e := range n.Edges {
 			switch e.Type {
 			case (C)(TokenMESSAGE):
 				processMessage(sb, e, ident+1)
 			case C(TokenIDENT):
 				sb.WriteString("\tname: ")
-				sb.WriteString(toString(e.Value))
+				sb.WriteString(lex.Text(e.Item))
 			case C(TokenTYPE):
 				sb.WriteString("\ttype: ")
-				sb.WriteString(toString(e.Value))
+				sb.WriteString(lex.Text(e.Item))
 			case C(TokenREPEATED):
 				sb.WriteString("\trepeated: true")
 			}
@@ -139,12 +142,12 @@ func processMessageFields[C ProtoToken, T byte](sb *strings.Builder, n *parse.No
 
 func Run[C ProtoToken, T byte, R string](r gio.Reader[T]) (R, error) {
 	var rootEOF C
-	l := (lex.Emitter[C, T])(lex.NewBuffer(initState[C, T], r))
+	l := (lex.Emitter[C, T])(lex.NewBuffer(newScanners[C, T]().initState, r))
 	t := parse.New(l, initParse[C, T], rootEOF)
 
 	// for t.Peek().Type != C(TokenEOF) {
 	// 	item := t.Next()
-	// 	fmt.Println(item.Type, toString(item.Value))
+	// 	fmt.Println(item.Type, lex.Text(item))
 	// }
 	// return "", nil
 
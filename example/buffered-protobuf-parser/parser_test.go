@@ -2,10 +2,13 @@ package protofile
 
 import (
 	_ "embed"
+	"errors"
 	"testing"
 
 	"github.com/zalgonoise/gbuf"
 	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+	"github.com/zalgonoise/lex/lextest"
 )
 
 //go:embed testdata/all.proto
@@ -21,3 +24,77 @@ func TestParser(t *testing.T) {
 	}
 	t.Log(str)
 }
+
+func TestLexNumbers(t *testing.T) {
+	input := []byte(`a = 1.5e10; b = 0x1F;`)
+	wants := []lex.Item[ProtoToken, byte]{
+		lex.NewItem(0, TokenIDENT, byte('a')),
+		lex.NewItem(2, TokenEQUAL, byte('=')),
+		{Pos: 4, Type: TokenVALUE, Value: []byte("1.5e10"), Data: 1.5e10},
+		lex.NewItem(10, TokenSEMICOL, byte(';')),
+		lex.NewItem(12, TokenIDENT, byte('b')),
+		lex.NewItem(14, TokenEQUAL, byte('=')),
+		{Pos: 16, Type: TokenVALUE, Value: []byte("0x1F"), Data: uint64(31)},
+		lex.NewItem(20, TokenSEMICOL, byte(';')),
+		lex.NewItem[ProtoToken, byte](21, TokenEOF),
+	}
+
+	lextest.Compare(t, wants, lextest.Lex(newScanners[ProtoToken, byte]().initState, input))
+}
+
+func TestParseInvalidNumber(t *testing.T) {
+	r := (gio.Reader[byte])(gbuf.NewReader([]byte(`message A { int32 a = 1x; }`)))
+
+	_, err := Run(r)
+	if !errors.Is(err, lex.ErrInvalidNumber) {
+		t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrInvalidNumber, err)
+	}
+}
+
+func TestLexStrings(t *testing.T) {
+	input := []byte(`syntax = "proto\x33"; a = 'b';`)
+	wants := []lex.Item[ProtoToken, byte]{
+		lex.NewItem(0, TokenSYNTAX, []byte("syntax")...),
+		lex.NewItem(7, TokenEQUAL, byte('=')),
+		{Pos: 9, Type: TokenVALUE, Value: []byte(`"proto\x33"`), Data: "proto3"},
+		lex.NewItem(20, TokenSEMICOL, byte(';')),
+		lex.NewItem(22, TokenIDENT, byte('a')),
+		lex.NewItem(24, TokenEQUAL, byte('=')),
+		{Pos: 26, Type: TokenVALUE, Value: []byte(`'b'`), Data: "b"},
+		lex.NewItem(29, TokenSEMICOL, byte(';')),
+		lex.NewItem[ProtoToken, byte](30, TokenEOF),
+	}
+
+	lextest.Compare(t, wants, lextest.Lex(newScanners[ProtoToken, byte]().initState, input))
+}
+
+func TestParseUnterminatedString(t *testing.T) {
+	r := (gio.Reader[byte])(gbuf.NewReader([]byte("syntax = \"proto3;\npackage generic;")))
+
+	_, err := Run(r)
+	if !errors.Is(err, lex.ErrUnterminatedString) {
+		t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrUnterminatedString, err)
+	}
+}
+
+func TestLexComments(t *testing.T) {
+	input := []byte("// header\nsyntax /* inline */ = 'proto3'; // trailing")
+	wants := []lex.Item[ProtoToken, byte]{
+		lex.NewItem(10, TokenSYNTAX, []byte("syntax")...),
+		lex.NewItem(30, TokenEQUAL, byte('=')),
+		{Pos: 32, Type: TokenVALUE, Value: []byte(`'proto3'`), Data: "proto3"},
+		lex.NewItem(40, TokenSEMICOL, byte(';')),
+		lex.NewItem[ProtoToken, byte](53, TokenEOF),
+	}
+
+	lextest.Compare(t, wants, lextest.Lex(newScanners[ProtoToken, byte]().initState, input))
+}
+
+func TestParseUnterminatedComment(t *testing.T) {
+	r := (gio.Reader[byte])(gbuf.NewReader([]byte("syntax = \"proto3\"; /* package generic;")))
+
+	_, err := Run(r)
+	if !errors.Is(err, lex.ErrUnterminatedComment) {
+		t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrUnterminatedComment, err)
+	}
+}
@@ -50,6 +50,29 @@ func BenchmarkLexer(b *testing.B) {
 		_ = lexeme
 	})
 
+	b.Run("Reset", func(b *testing.B) {
+		input := []rune(`string with {template} in it even { in {twice} out } in a row, or {even} { more {examples} if necessary}.`)
+		var lexeme lex.Item[TextToken, rune]
+		var eof lex.Item[TextToken, rune]
+		reader := gbuf.NewReader(input)
+		l := lex.NewBuffer(initState[TextToken, rune], (gio.Reader[rune])(reader))
+
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			reader.Reset(input)
+			_ = l.Reset(initState[TextToken, rune], reader)
+			for {
+				lex := l.NextItem()
+				if lex.Type == eof.Type {
+					break
+				}
+				lexeme = lex
+			}
+		}
+		_ = lexeme
+	})
+
 	b.Run("SqueezeTheBuffer", func(b *testing.B) {
 		input := []rune(`string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row.string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row. string with {template} in it even { in {twice} out } in a row.`)
 		var lexeme lex.Item[TextToken, rune]
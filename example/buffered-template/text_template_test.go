@@ -5,6 +5,8 @@ import (
 
 	"github.com/zalgonoise/gbuf"
 	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+	"github.com/zalgonoise/parse"
 )
 
 func TestRun(t *testing.T) {
@@ -36,7 +38,7 @@ func TestRun(t *testing.T) {
 
 	t.Run("errored", func(t *testing.T) {
 		wants := "string with "
-		wantsErr := "parse error on line: 0"
+		wantsErr := "parse error on line: 12"
 		input := `string with {template in it`
 
 		buf := (gio.Reader[rune])(gbuf.NewReader([]rune(input)))
@@ -52,3 +54,46 @@ func TestRun(t *testing.T) {
 		}
 	})
 }
+
+func TestParseItems(t *testing.T) {
+	t.Run("ErrorToken", func(t *testing.T) {
+		for _, test := range []struct {
+			name  string
+			items []lex.Item[TextToken, rune]
+			wants string
+		}{
+			{
+				name: "AfterText",
+				items: []lex.Item[TextToken, rune]{
+					lex.NewItem[TextToken, rune](0, TokenIDENT, []rune("with ")...),
+					lex.NewItem[TextToken, rune](5, TokenError, '}'),
+					lex.NewItem[TextToken, rune](6, TokenIDENT, []rune("text")...),
+				},
+				wants: "with ",
+			},
+			{
+				name: "InTemplate",
+				items: []lex.Item[TextToken, rune]{
+					lex.NewItem[TextToken, rune](0, TokenLBRACE, '{'),
+					lex.NewItem[TextToken, rune](1, TokenIDENT, []rune("tmpl")...),
+					lex.NewItem[TextToken, rune](5, TokenError, '}'),
+					lex.NewItem[TextToken, rune](6, TokenRBRACE, '}'),
+				},
+				wants: "",
+			},
+		} {
+			t.Run(test.name, func(t *testing.T) {
+				tree := parse.New(lex.FromItems(test.items...), initParse[TextToken, rune], TokenEOF)
+				tree.Parse()
+
+				out, err := processFn[TextToken, rune, string](tree)
+				if err == nil || err.Error() != "parse error on line: 5" {
+					t.Errorf("unexpected error: wanted %s ; got %v", "parse error on line: 5", err)
+				}
+				if test.wants != out {
+					t.Errorf("unexpected output: wanted %q ; got %q", test.wants, out)
+				}
+			})
+		}
+	})
+}
@@ -45,4 +45,24 @@ func BenchmarkLexer(b *testing.B) {
 		}
 		_ = lexeme
 	})
+	b.Run("Reset", func(b *testing.B) {
+		input := []rune(`string with {template} in it even { in {twice} out } in a row, or {even} { more {examples} if necessary}.`)
+		var lexeme lex.Item[TextToken, rune]
+		var eof lex.Item[TextToken, rune]
+		l := lex.New(initState[TextToken, rune], input)
+
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			l.Reset(initState[TextToken, rune], input)
+			for {
+				lex := l.NextItem()
+				if lex.Type == eof.Type {
+					break
+				}
+				lexeme = lex
+			}
+		}
+		_ = lexeme
+	})
 }
@@ -0,0 +1,16 @@
+package impl
+
+import (
+	"testing"
+
+	"github.com/zalgonoise/lex/lextest"
+)
+
+func FuzzLexer(f *testing.F) {
+	f.Add([]byte(`with {tmpl}.`))
+	f.Add([]byte(`string with {template} in it even { in {twice} out } in a row.`))
+	f.Add([]byte(`string with {template in it`))
+	f.Add([]byte(`}}{`))
+
+	lextest.Fuzz(f, initState[TextToken, rune])
+}
@@ -7,15 +7,16 @@ import (
 // initParse describes the ParseFn to kick off the parser. It is also the default fallback
 // for any other ParseFn
 func initParse[C TextToken, T rune](t *parse.Tree[C, T]) parse.ParseFn[C, T] {
-	for t.Peek().Type != C(TokenEOF) {
-		switch t.Peek().Type {
-		case (C)(TokenIDENT):
-			return parseText[C, T]
-		case (C)(TokenLBRACE), (C)(TokenRBRACE):
-			return parseTemplate[C, T]
-		}
+	switch t.Peek().Type {
+	case (C)(TokenIDENT):
+		return parseText[C, T]
+	case (C)(TokenLBRACE), (C)(TokenRBRACE):
+		return parseTemplate[C, T]
+	case (C)(TokenError):
+		return parseError[C, T]
+	default:
+		return nil
 	}
-	return nil
 }
 
 // parseText consumes the next item as a text token, creating a node for it under the
@@ -41,3 +42,11 @@ func parseTemplate[C TextToken, T rune](t *parse.Tree[C, T]) parse.ParseFn[C, T]
 	}
 	return initParse[C, T]
 }
+
+// parseError consumes the next item as an error token, creating a node for it next to the
+// current one in the tree, to be reported when processing the tree
+func parseError[C TextToken, T rune](t *parse.Tree[C, T]) parse.ParseFn[C, T] {
+	t.Set(t.Parent())
+	t.Node(t.Next())
+	return initParse[C, T]
+}
@@ -24,6 +24,8 @@ func processFn[C TextToken, T rune, R string](t *parse.Tree[C, T]) (R, error) {
 				return (R)(sb.String()), err
 			}
 			sb.WriteString((string)(proc))
+		case (C)(TokenError):
+			return (R)(sb.String()), processError[C, T](n)
 		}
 	}
 
@@ -66,6 +68,8 @@ func processTemplate[C TextToken, T rune, R string](n *parse.Node[C, T]) (R, err
 				return (R)(sb.String()), err
 			}
 			sb.WriteString((string)(proc))
+		case (C)(TokenError):
+			return (R)(sb.String()), processError[C, T](node)
 		}
 	}
 	if !ended {
@@ -75,3 +79,8 @@ func processTemplate[C TextToken, T rune, R string](n *parse.Node[C, T]) (R, err
 	sb.WriteString("<<")
 	return (R)(sb.String()), nil
 }
+
+// processError returns the parse error for an error node
+func processError[C TextToken, T rune](n *parse.Node[C, T]) error {
+	return fmt.Errorf("parse error on line: %d", n.Pos)
+}
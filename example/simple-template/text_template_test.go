@@ -1,6 +1,11 @@
 package impl
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/zalgonoise/lex"
+	"github.com/zalgonoise/parse"
+)
 
 func TestRun(t *testing.T) {
 	t.Run("simple", func(t *testing.T) {
@@ -41,4 +46,72 @@ func TestRun(t *testing.T) {
 			t.Errorf("unexpected output error: wanted %s ; got %s", wants, out)
 		}
 	})
+
+}
+
+func TestParseItems(t *testing.T) {
+	t.Run("unterminated", func(t *testing.T) {
+		wants := "with "
+		wantsErr := "parse error on line: 5"
+		l := lex.FromItems(
+			lex.NewItem[TextToken, rune](0, TokenIDENT, []rune("with ")...),
+			lex.NewItem[TextToken, rune](5, TokenLBRACE, '{'),
+			lex.NewItem[TextToken, rune](6, TokenIDENT, []rune("tmpl")...),
+		)
+		tree := parse.New(l, initParse[TextToken, rune], TokenEOF)
+		tree.Parse()
+
+		out, err := processFn[TextToken, rune, string](tree)
+		if err == nil {
+			t.Errorf("expected error not to be nil")
+			return
+		}
+		if wantsErr != err.Error() {
+			t.Errorf("unexpected output error: wanted %s ; got %s", wantsErr, err.Error())
+		}
+		if wants != out {
+			t.Errorf("unexpected output error: wanted %s ; got %s", wants, out)
+		}
+	})
+
+	t.Run("ErrorToken", func(t *testing.T) {
+		for _, test := range []struct {
+			name  string
+			items []lex.Item[TextToken, rune]
+			wants string
+		}{
+			{
+				name: "AfterText",
+				items: []lex.Item[TextToken, rune]{
+					lex.NewItem[TextToken, rune](0, TokenIDENT, []rune("with ")...),
+					lex.NewItem[TextToken, rune](5, TokenError, '}'),
+					lex.NewItem[TextToken, rune](6, TokenIDENT, []rune("text")...),
+				},
+				wants: "with ",
+			},
+			{
+				name: "InTemplate",
+				items: []lex.Item[TextToken, rune]{
+					lex.NewItem[TextToken, rune](0, TokenLBRACE, '{'),
+					lex.NewItem[TextToken, rune](1, TokenIDENT, []rune("tmpl")...),
+					lex.NewItem[TextToken, rune](5, TokenError, '}'),
+					lex.NewItem[TextToken, rune](6, TokenRBRACE, '}'),
+				},
+				wants: "",
+			},
+		} {
+			t.Run(test.name, func(t *testing.T) {
+				tree := parse.New(lex.FromItems(test.items...), initParse[TextToken, rune], TokenEOF)
+				tree.Parse()
+
+				out, err := processFn[TextToken, rune, string](tree)
+				if err == nil || err.Error() != "parse error on line: 5" {
+					t.Errorf("unexpected error: wanted %s ; got %v", "parse error on line: 5", err)
+				}
+				if test.wants != out {
+					t.Errorf("unexpected output: wanted %q ; got %q", test.wants, out)
+				}
+			})
+		}
+	})
 }
@@ -0,0 +1,67 @@
+package lex_test
+
+import "github.com/zalgonoise/lex"
+
+// the token types emitted by the test StateFns and scanners, shared across the test files
+const (
+	tokenEOF uint = iota
+	tokenError
+	tokenIdent
+	tokenPeriod
+	tokenComment
+
+	// Indent
+	tokenIndent
+	tokenDedent
+	tokenNewline
+
+	// Number and Quoted
+	tokenInt
+	tokenFloat
+	tokenString
+
+	// Operators
+	tokenGT
+	tokenShr
+	tokenShrAssign
+	tokenGE
+	tokenAssign
+	tokenEQ
+	tokenNE
+	tokenEllipsis
+
+	// hints
+	tokenRegex
+
+	// Keywords
+	tokenSelect
+	tokenFrom
+	tokenEte
+	tokenKind
+)
+
+// scanState returns a StateFn that calls the scan fragment `scan` (such as a scanner's `Scan()`
+// method) on each token, ignoring the whitespace between them. The units that the fragment does
+// not scan are emitted as ident items for runs of letters, or as period items otherwise
+func scanState[T rune | byte](scan func(l lex.Lexer[uint, T]) bool) lex.StateFn[uint, T] {
+	var state lex.StateFn[uint, T]
+	state = func(l lex.Lexer[uint, T]) lex.StateFn[uint, T] {
+		l.AcceptRun(func(item T) bool { return item == ' ' || item == '\n' })
+		l.Ignore()
+
+		switch {
+		case l.Cur() == 0:
+			l.Emit(tokenEOF)
+			return nil
+		case scan(l):
+		case l.Cur() >= 'a' && l.Cur() <= 'z':
+			l.AcceptRun(func(item T) bool { return item >= 'a' && item <= 'z' })
+			l.Emit(tokenIdent)
+		default:
+			l.Next()
+			l.Emit(tokenPeriod)
+		}
+		return state
+	}
+	return state
+}
@@ -0,0 +1,46 @@
+package lex
+
+import "errors"
+
+// ErrRelex is a preset error for items that cannot be lexed again, as they are no longer (or
+// not yet) available in the lexer's input
+var ErrRelex = errors.New("item cannot be relexed")
+
+// Hinter describes the behavior of a lexer that takes feedback from its consumer (such as a
+// parser), for grammars with context-sensitive tokens, like `/` as a division operator versus
+// the start of a regular expression, or `>>` as a shift operator versus two closing brackets
+//
+// The consumer sets a hint with `SetHint()`, which the lexer's StateFns read with the
+// HintReader's `Hint()` method; and if an item was already lexed without the right context, it
+// can be lexed again with `Relex()`. Both Lex and LexBuffer implement Hinter, and so does a
+// TokenStream that wraps a Hinter, discarding its buffered lookahead when relexing.
+//
+// A parse.Tree does not expose its Emitter to the ParseFns, so the consumer should create the
+// lexer (or a TokenStream wrapping it) itself, pass it to `parse.New()`, and have the ParseFns
+// reach it as closures or methods on a type holding it. Note that a parse.Tree also keeps the
+// item returned by its `Peek()` method: after relexing a peeked item, the ParseFn must drop it
+// with the Tree's `Next()` method, so that the next call returns the relexed item
+type Hinter[C comparable, T any] interface {
+	// SetHint sets the hint `hint` for the lexer's StateFns to read with `Hint()`
+	SetHint(hint any)
+	// Relex rewinds the lexer to the position of the item `item`, so that its units are lexed
+	// again with the StateFn `state`, or the lexer's starting StateFn if nil
+	Relex(item Item[C, T], state StateFn[C, T]) error
+}
+
+var (
+	_ Hinter[uint8, any] = &Lex[uint8, any]{}
+	_ Hinter[uint8, any] = &LexBuffer[uint8, any]{}
+	_ Hinter[uint8, any] = &TokenStream[uint8, any]{}
+)
+
+// drain discards all items queued in the channel `items`
+func drain[C comparable, T any](items chan Item[C, T]) {
+	for {
+		select {
+		case <-items:
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package lex_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zalgonoise/gbuf"
+	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+)
+
+type regexHint struct{}
+
+// hintState describes a StateFn that emits words, and slashes either as division operators
+// (as period items) or as the start of a regular expression, depending on the lexer's hint
+func hintState[C uint, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	l.AcceptRun(func(item T) bool { return item == ' ' })
+	l.Ignore()
+
+	switch l.Cur() {
+	case 0:
+		l.Emit((C)(tokenEOF))
+		return nil
+	case '/':
+		l.Next()
+		if _, ok := l.(lex.HintReader).Hint().(regexHint); ok && l.(lex.Acceptor[T]).AcceptUntil(func(item T) bool { return item == '/' }) {
+			l.Next()
+			l.Emit((C)(tokenRegex))
+			return hintState[C, T]
+		}
+		l.Emit((C)(tokenPeriod))
+	default:
+		l.AcceptRun(func(item T) bool { return item != ' ' && item != '/' && item != 0 })
+		l.Emit((C)(tokenIdent))
+	}
+	return hintState[C, T]
+}
+
+type hintLexer interface {
+	lex.Emitter[uint, rune]
+	lex.Hinter[uint, rune]
+}
+
+func TestHint(t *testing.T) {
+	input := []rune("/b/ / a")
+
+	buffer := lex.NewBuffer(hintState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(input)))
+	buffer.Size(0)
+
+	for _, test := range []struct {
+		name string
+		l    hintLexer
+	}{
+		{"Lex", lex.New(hintState[uint, rune], input)},
+		{"LexBuffer", lex.NewBuffer(hintState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(input)))},
+		{"LexBufferNoLookback", buffer},
+		{"TokenStream", lex.NewTokenStream[uint, rune](lex.New(hintState[uint, rune], input))},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			// an operand is expected first: a slash starts a regular expression
+			item := test.l.NextItem()
+			if item.Type != tokenPeriod || item.Pos != 0 {
+				t.Errorf("unexpected item: wanted a division operator on position %d ; got %v", 0, item)
+			}
+			test.l.SetHint(regexHint{})
+			if err := test.l.Relex(item, nil); err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			item = test.l.NextItem()
+			if item.Type != tokenRegex || item.Pos != 0 || string(item.Value) != "/b/" {
+				t.Errorf("unexpected item: wanted a regular expression on position %d ; got %v", 0, item)
+			}
+
+			test.l.SetHint(nil)
+			items := lex.Collect[uint, rune](test.l)
+			if len(items) != 3 {
+				t.Errorf("token slice length mismatch error: wanted %d ; got %d", 3, len(items))
+				return
+			}
+			if items[0].Type != tokenPeriod || items[0].Pos != 4 || string(items[1].Value) != "a" {
+				t.Errorf("unexpected items: %v", items)
+			}
+
+			if err := test.l.Relex(lex.NewItem[uint, rune](len(input)+1, tokenIdent), nil); !errors.Is(err, lex.ErrRelex) {
+				t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrRelex, err)
+			}
+		})
+	}
+}
+
+// valueState describes a StateFn that emits each word with a replaced value, or as is
+// with the lexer's hint
+func valueState[C uint, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	l.AcceptRun(func(item T) bool { return item == ' ' })
+	l.Ignore()
+
+	if l.Cur() == 0 {
+		l.Emit((C)(tokenEOF))
+		return nil
+	}
+	l.AcceptRun(func(item T) bool { return item != ' ' && item != 0 })
+	if l.(lex.HintReader).Hint() != nil {
+		l.Emit((C)(tokenIdent))
+		return valueState[C, T]
+	}
+	l.(lex.ValueEmitter[C, T]).EmitValue((C)(tokenIdent), []T{'z', 'z', 'z', 'z'})
+	return valueState[C, T]
+}
+
+func TestRelexValue(t *testing.T) {
+	input := []rune("ab cd")
+
+	buffer := lex.NewBuffer(valueState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(input)))
+	buffer.Size(0)
+	streamBuffer := lex.NewBuffer(valueState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(input)))
+	streamBuffer.Size(0)
+
+	for _, test := range []struct {
+		name string
+		l    hintLexer
+	}{
+		{"Lex", lex.New(valueState[uint, rune], input)},
+		{"LexBufferNoLookback", buffer},
+		{"TokenStream", lex.NewTokenStream[uint, rune](streamBuffer)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			item := test.l.NextItem()
+			if string(item.Value) != "zzzz" {
+				t.Errorf("unexpected item: wanted a replaced value ; got %v", item)
+			}
+			test.l.SetHint(true)
+			if err := test.l.Relex(item, nil); err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			// the item is relexed from the original input, not from its emitted value
+			if item = test.l.NextItem(); item.Pos != 0 || string(item.Value) != "ab" {
+				t.Errorf("unexpected item: wanted %q on position %d ; got %v", "ab", 0, item)
+			}
+			if item = test.l.NextItem(); item.Pos != 3 || string(item.Value) != "cd" {
+				t.Errorf("unexpected item: wanted %q on position %d ; got %v", "cd", 3, item)
+			}
+		})
+	}
+}
+
+func TestTokenStreamRelex(t *testing.T) {
+	input := []rune("/b/ / a")
+	s := lex.NewTokenStream[uint, rune](lex.New(hintState[uint, rune], input))
+
+	// pull lookahead items before relexing the first one
+	if item := s.Peek(2); item.Pos != 2 {
+		t.Errorf("unexpected item: wanted position %d ; got %v", 2, item)
+	}
+	item := s.Next()
+
+	s.SetHint(regexHint{})
+	if err := s.Relex(item, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	if item = s.Next(); item.Type != tokenRegex || string(item.Value) != "/b/" {
+		t.Errorf("unexpected item: wanted a regular expression ; got %v", item)
+	}
+
+	s.SetHint(nil)
+	if item = s.Next(); item.Type != tokenPeriod || item.Pos != 4 {
+		t.Errorf("unexpected item: wanted a division operator on position %d ; got %v", 4, item)
+	}
+
+	if err := lex.NewTokenStream[uint, rune](lex.EmitterFunc[uint, rune](func() lex.Item[uint, rune] {
+		return lex.Item[uint, rune]{}
+	})).Relex(item, nil); !errors.Is(err, lex.ErrRelex) {
+		t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrRelex, err)
+	}
+}
@@ -0,0 +1,219 @@
+package lex
+
+import (
+	"errors"
+
+	"github.com/zalgonoise/lex/class"
+)
+
+// DefaultTabSize is the default number of columns between tab stops, for an Indent using the
+// TabStop policy
+const DefaultTabSize = 8
+
+var (
+	// ErrInconsistentDedent is a preset error for lines that dedent to a column that does not
+	// match any of the enclosing indentation levels
+	ErrInconsistentDedent = errors.New("dedent does not match any outer indentation level")
+	// ErrTabIndent is a preset error for tabs in a line's indentation, when rejected by the
+	// Indent's TabPolicy
+	ErrTabIndent = errors.New("tab in indentation")
+)
+
+// TabPolicy defines how an Indent measures the tabs in a line's indentation
+type TabPolicy uint8
+
+const (
+	// TabStop advances a tab to the next tab stop, as a multiple of the tab size (the default)
+	TabStop TabPolicy = iota
+	// TabReject reports tabs in a line's indentation as an error (with ErrTabIndent)
+	TabReject
+)
+
+// IndentTokens describes the token types emitted by an Indent
+type IndentTokens[C comparable] struct {
+	// Indent is the token type for the zero-width items emitted when a line is indented
+	// deeper than the previous one
+	Indent C
+	// Dedent is the token type for the zero-width items emitted for each indentation level
+	// closed by a line
+	Dedent C
+	// Newline is the token type for the items emitted at the end of each non-blank line
+	Newline C
+	// Error is the token type for the error items, on tabs (with the TabReject policy) or
+	// inconsistent dedents
+	Error C
+}
+
+// Indent tracks the indentation of each line in the input, for indentation-sensitive languages
+// (such as Python or YAML); by wrapping a StateFn that lexes the contents of each line
+//
+// On the beginning of each line, the leading whitespace is measured and ignored, emitting an
+// Indent item if the line is indented deeper than the previous one, or one Dedent item for each
+// indentation level it closes. The newlines ending each line are emitted as Newline items,
+// while blank lines are ignored. When the input ends, a zero-width Newline item closes the last
+// line (if not closed yet), followed by any pending Dedent items and an EOF item
+//
+// Each indentation level is entered as a nesting level in the lexer (with its `Enter()` method),
+// counting towards its MaxDepth limit; if the lexer is a Nester.
+//
+// The wrapped StateFn is called for the contents of each line, and must not consume newlines
+// nor emit EOF items. An Indent holds the state of a single lexer run, so it must not be shared
+// across lexers
+type Indent[C comparable, T class.Char] struct {
+	tokens  IndentTokens[C]
+	policy  TabPolicy
+	tabSize int
+	next    StateFn[C, T]
+
+	stack []int
+	col   int
+	open  bool
+}
+
+// NewIndent creates an Indent emitting the token types in `tokens`, wrapping the StateFn
+// `state` that lexes the contents of each line
+//
+// The lexer should be created with the Indent's `Init()` method as its starting StateFn
+func NewIndent[C comparable, T class.Char](tokens IndentTokens[C], state StateFn[C, T]) *Indent[C, T] {
+	return &Indent[C, T]{
+		tokens:  tokens,
+		tabSize: DefaultTabSize,
+		next:    state,
+		stack:   []int{0},
+	}
+}
+
+// Tabs sets the Indent's TabPolicy and tab size (for the TabStop policy)
+//
+// A tab size of zero or below uses DefaultTabSize
+func (i *Indent[C, T]) Tabs(policy TabPolicy, size int) {
+	if size <= 0 {
+		size = DefaultTabSize
+	}
+	i.policy = policy
+	i.tabSize = size
+}
+
+// Depth returns the current indentation level
+func (i *Indent[C, T]) Depth() int {
+	return len(i.stack) - 1
+}
+
+// Init is the starting StateFn for the lexer, which measures the indentation on the beginning
+// of a line
+func (i *Indent[C, T]) Init(l Lexer[C, T]) StateFn[C, T] {
+	var col int
+measure:
+	for {
+		switch l.Cur() {
+		case ' ':
+			col++
+		case '\t':
+			if i.policy == TabReject {
+				l.Ignore()
+				l.Next()
+				emitError(l, i.tokens.Error, ErrTabIndent)
+				return nil
+			}
+			col += i.tabSize - col%i.tabSize
+		default:
+			break measure
+		}
+		l.Next()
+	}
+
+	switch {
+	case atEOF(l):
+		l.Ignore()
+		return i.end
+	case l.Cur() == '\n':
+		// blank line
+		l.Next()
+		l.Ignore()
+		return i.Init
+	}
+
+	l.Ignore()
+	i.open = true
+	i.col = col
+	switch top := i.stack[len(i.stack)-1]; {
+	case col > top:
+		if !enter(l) {
+			return nil
+		}
+		i.stack = append(i.stack, col)
+		emitEmpty(l, i.tokens.Indent)
+	case col < top:
+		return i.dedent
+	}
+	return i.line
+}
+
+// dedent emits a Dedent item for each indentation level closed by the current line, one at
+// a time
+func (i *Indent[C, T]) dedent(l Lexer[C, T]) StateFn[C, T] {
+	top := i.stack[len(i.stack)-1]
+	switch {
+	case i.col < top:
+		i.stack = i.stack[:len(i.stack)-1]
+		leave(l)
+		emitEmpty(l, i.tokens.Dedent)
+		return i.dedent
+	case i.col > top:
+		emitError(l, i.tokens.Error, ErrInconsistentDedent)
+		return nil
+	}
+	return i.line
+}
+
+// line runs the wrapped StateFn over the contents of the current line, until its newline
+func (i *Indent[C, T]) line(l Lexer[C, T]) StateFn[C, T] {
+	switch {
+	case atEOF(l):
+		return i.end
+	case l.Cur() == '\n':
+		l.Ignore()
+		l.Next()
+		l.Emit(i.tokens.Newline)
+		i.open = false
+		return i.Init
+	}
+
+	if i.next == nil {
+		return i.end
+	}
+	i.next = i.next(l)
+	return i.line
+}
+
+// end closes the last line and any open indentation levels, one item at a time, before
+// emitting an EOF item
+func (i *Indent[C, T]) end(l Lexer[C, T]) StateFn[C, T] {
+	l.Ignore()
+	if i.open {
+		i.open = false
+		emitEmpty(l, i.tokens.Newline)
+		return i.end
+	}
+	if len(i.stack) > 1 {
+		i.stack = i.stack[:len(i.stack)-1]
+		leave(l)
+		emitEmpty(l, i.tokens.Dedent)
+		return i.end
+	}
+
+	var eof C
+	l.Emit(eof)
+	return nil
+}
+
+// atEOF returns true if the lexer's cursor is at the end of the input, as detected by its
+// position (rather than by the EOF unit, which may be set with WithEOF)
+func atEOF[C comparable, T any](l Lexer[C, T]) bool {
+	pos := l.Pos()
+	if l.Next(); l.Pos() == pos {
+		return true
+	}
+	l.Prev()
+	return false
+}
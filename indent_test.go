@@ -0,0 +1,143 @@
+package lex_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zalgonoise/gbuf"
+	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+	"github.com/zalgonoise/lex/lextest"
+)
+
+var indentTokens = lex.IndentTokens[uint]{
+	Indent:  tokenIndent,
+	Dedent:  tokenDedent,
+	Newline: tokenNewline,
+	Error:   tokenError,
+}
+
+// wordState describes a StateFn that emits the words in a line, ignoring the spaces between them
+func wordState[C uint, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	l.AcceptRun(func(item T) bool { return item == ' ' })
+	l.Ignore()
+	l.AcceptRun(func(item T) bool { return item != ' ' && item != '\n' && item != 0 })
+	if l.Width() > 0 {
+		l.Emit((C)(tokenIdent))
+	}
+	return wordState[C, T]
+}
+
+func TestIndent(t *testing.T) {
+	input := []rune("if a:\n  b\n\n  if c:\n\td\ne")
+	wants := []lex.Item[uint, rune]{
+		lex.NewItem(0, tokenIdent, []rune("if")...),
+		lex.NewItem(3, tokenIdent, []rune("a:")...),
+		lex.NewItem(5, tokenNewline, '\n'),
+		lex.NewItem[uint, rune](8, tokenIndent),
+		lex.NewItem(8, tokenIdent, 'b'),
+		lex.NewItem(9, tokenNewline, '\n'),
+		lex.NewItem(13, tokenIdent, []rune("if")...),
+		lex.NewItem(16, tokenIdent, []rune("c:")...),
+		lex.NewItem(18, tokenNewline, '\n'),
+		lex.NewItem[uint, rune](20, tokenIndent),
+		lex.NewItem(20, tokenIdent, 'd'),
+		lex.NewItem(21, tokenNewline, '\n'),
+		lex.NewItem[uint, rune](22, tokenDedent),
+		lex.NewItem[uint, rune](22, tokenDedent),
+		lex.NewItem(22, tokenIdent, 'e'),
+		lex.NewItem[uint, rune](23, tokenNewline),
+		lex.NewItem[uint, rune](23, tokenEOF),
+	}
+
+	t.Run("Lex", func(t *testing.T) {
+		ind := lex.NewIndent(indentTokens, wordState[uint, rune])
+		lextest.Compare(t, wants, lex.Collect[uint, rune](lex.New(ind.Init, input)))
+	})
+
+	t.Run("LexBuffer", func(t *testing.T) {
+		ind := lex.NewIndent(indentTokens, wordState[uint, rune])
+		lextest.Compare(t, wants, lex.Collect[uint, rune](
+			lex.NewBuffer(ind.Init, (gio.Reader[rune])(gbuf.NewReader(input))),
+		))
+	})
+
+	t.Run("UnclosedLevels", func(t *testing.T) {
+		ind := lex.NewIndent(indentTokens, wordState[uint, rune])
+		lextest.Compare(t, []lex.Item[uint, rune]{
+			lex.NewItem(0, tokenIdent, 'a'),
+			lex.NewItem(1, tokenNewline, '\n'),
+			lex.NewItem[uint, rune](4, tokenIndent),
+			lex.NewItem(4, tokenIdent, 'b'),
+			lex.NewItem(5, tokenNewline, '\n'),
+			lex.NewItem[uint, rune](6, tokenDedent),
+			lex.NewItem[uint, rune](6, tokenEOF),
+		}, lex.Collect[uint, rune](lex.New(ind.Init, []rune("a\n  b\n"))))
+	})
+
+	t.Run("InconsistentDedent", func(t *testing.T) {
+		ind := lex.NewIndent(indentTokens, wordState[uint, rune])
+		items := lex.Collect[uint, rune](lex.New(ind.Init, []rune("a\n    b\n  c\n")))
+		item := items[len(items)-2]
+		if item.Type != tokenError || item.Pos != 10 || !errors.Is(item.Err, lex.ErrInconsistentDedent) {
+			t.Errorf("unexpected item: wanted an inconsistent dedent error on position %d ; got %v", 10, item)
+		}
+	})
+
+	t.Run("TabReject", func(t *testing.T) {
+		ind := lex.NewIndent(indentTokens, wordState[uint, rune])
+		ind.Tabs(lex.TabReject, 0)
+		items := lex.Collect[uint, rune](lex.New(ind.Init, []rune("a\n \tb\n")))
+		item := items[len(items)-2]
+		if item.Type != tokenError || item.Pos != 3 || !errors.Is(item.Err, lex.ErrTabIndent) {
+			t.Errorf("unexpected item: wanted a tab indent error on position %d ; got %v", 3, item)
+		}
+	})
+}
+
+func TestIndentDeepNesting(t *testing.T) {
+	// closing more levels than lex.DefaultMaxStall, one zero-width item at a time
+	levels := lex.DefaultMaxStall + 64
+	var sb strings.Builder
+	for i := 0; i <= levels; i++ {
+		sb.WriteString(strings.Repeat(" ", i))
+		sb.WriteString("a\n")
+	}
+
+	ind := lex.NewIndent(indentTokens, wordState[uint, rune])
+	items := lex.Collect[uint, rune](lex.New(ind.Init, []rune(sb.String())))
+
+	var dedents int
+	for _, item := range items {
+		if item.Err != nil {
+			t.Errorf("unexpected error: %v", item.Err)
+			return
+		}
+		if item.Type == tokenDedent {
+			dedents++
+		}
+	}
+	if dedents != levels {
+		t.Errorf("unexpected number of dedent items: wanted %d ; got %d", levels, dedents)
+	}
+}
+
+func TestIndentMaxDepth(t *testing.T) {
+	input := []rune("a\n b\n  c\n   d\n")
+
+	ind := lex.NewIndent(indentTokens, wordState[uint, rune])
+	l, err := lex.NewLex(ind.Init, input, lex.WithLimits(lex.Limits{MaxDepth: 2}))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	items := lex.Collect[uint, rune](l)
+	if last := items[len(items)-1]; !errors.Is(last.Err, lex.ErrMaxDepth) || last.Pos != 12 {
+		t.Errorf("unexpected EOF item: wanted error %v on position %d ; got %v", lex.ErrMaxDepth, 12, last)
+	}
+	if ind.Depth() != 2 {
+		t.Errorf("unexpected depth: wanted %d ; got %d", 2, ind.Depth())
+	}
+}
@@ -1,10 +1,34 @@
 package lex
 
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
 // Item represents a set of any type of tokens identified by a comparable type
+//
+// Items carrying a non-nil Err are diagnostic items raised by the lexer itself, such as
+// when a StateFn exceeds the lexer's Limits
+//
+// Leading and Trailing hold the ignored units (trivia) surrounding the item, when the lexer is
+// set with a TriviaMode other than TriviaNone
+//
+// Channel is the channel the item was emitted on; which is the DefaultChannel for all items
+// returned by a lexer's `NextItem()` method
+//
+// Data holds an optional semantic payload set by the StateFn that emitted the item (with
+// `EmitData()`), such as a decoded number or an unescaped string; which can be read as a typed
+// value with the Data function
 type Item[T comparable, V any] struct {
-	Pos   int
-	Type  T
-	Value []V
+	Pos      int
+	Type     T
+	Value    []V
+	Err      error
+	Leading  []V
+	Trailing []V
+	Channel  Channel
+	Data     any
 }
 
 // NewItem creates an Item with type `T` and values `[]V`
@@ -15,3 +39,116 @@ func NewItem[T comparable, V any](pos int, itemType T, value ...V) Item[T, V] {
 		Value: value,
 	}
 }
+
+// End returns the position right after the item's value, as its starting position plus the
+// length of its value
+//
+// For items emitted with a replaced value (with EmitValue), it is based on the length of the
+// replaced value rather than on the units consumed from the input
+func (i Item[T, V]) End() int {
+	return i.Pos + len(i.Value)
+}
+
+// Len returns the length of the item's value
+func (i Item[T, V]) Len() int {
+	return len(i.Value)
+}
+
+// Span returns the item's starting and ending positions, as with `End()`
+func (i Item[T, V]) Span() (start, end int) {
+	return i.Pos, i.End()
+}
+
+// String implements the fmt.Stringer interface, returning the item's token type, its quoted
+// value and its position (and its error, if set)
+//
+// Values of byte or rune types are quoted as strings; while any other type is formatted with
+// the `%v` verb
+func (i Item[T, V]) String() string {
+	if i.Err != nil {
+		return fmt.Sprintf("%v %s on position %d: %v", i.Type, i.Quote(), i.Pos, i.Err)
+	}
+	return fmt.Sprintf("%v %s on position %d", i.Type, i.Quote(), i.Pos)
+}
+
+// Data returns the item's Data as a value of type D, and true; or the zero value of D and false
+// if the item holds no Data of that type
+func Data[D any, T comparable, V any](item Item[T, V]) (D, bool) {
+	data, ok := item.Data.(D)
+	return data, ok
+}
+
+// Equal returns true if both items `a` and `b` are the same, with the same position, token type,
+// value, trivia, channel and data; and with errors holding the same message, if set
+func Equal[T comparable, V comparable](a, b Item[T, V]) bool {
+	if a.Pos != b.Pos || a.Type != b.Type || a.Channel != b.Channel || !reflect.DeepEqual(a.Data, b.Data) {
+		return false
+	}
+	if (a.Err == nil) != (b.Err == nil) || (a.Err != nil && a.Err.Error() != b.Err.Error()) {
+		return false
+	}
+	return equalUnits(a.Value, b.Value) && equalUnits(a.Leading, b.Leading) && equalUnits(a.Trailing, b.Trailing)
+}
+
+// Text returns the item's value as a string, for items over byte or rune units
+//
+// Byte units are read as UTF-8 encoded text
+func Text[T comparable, V ~byte | ~rune](item Item[T, V]) string {
+	switch v := any(item.Value).(type) {
+	case []byte:
+		return string(v)
+	case []rune:
+		return string(v)
+	}
+
+	var zero V
+	if ^zero > 0 {
+		// unsigned: a byte type
+		buf := make([]byte, len(item.Value))
+		for idx := range item.Value {
+			buf[idx] = (byte)(item.Value[idx])
+		}
+		return string(buf)
+	}
+
+	buf := make([]rune, len(item.Value))
+	for idx := range item.Value {
+		buf[idx] = (rune)(item.Value[idx])
+	}
+	return string(buf)
+}
+
+// equalUnits returns true if both slices `a` and `b` hold the same units
+func equalUnits[V comparable](a, b []V) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+// Quote returns the item's value quoted as a string if its units are of a byte or rune type, or
+// formatted with the `%v` verb otherwise
+func (i Item[T, V]) Quote() string {
+	v := reflect.ValueOf(i.Value)
+	switch v.Type().Elem().Kind() {
+	case reflect.Uint8:
+		buf := make([]byte, v.Len())
+		for idx := range buf {
+			buf[idx] = (byte)(v.Index(idx).Uint())
+		}
+		return strconv.Quote((string)(buf))
+	case reflect.Int32:
+		buf := make([]rune, v.Len())
+		for idx := range buf {
+			buf[idx] = (rune)(v.Index(idx).Int())
+		}
+		return strconv.Quote((string)(buf))
+	default:
+		return fmt.Sprintf("%v", i.Value)
+	}
+}
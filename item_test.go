@@ -0,0 +1,82 @@
+package lex_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zalgonoise/lex"
+)
+
+type byteUnit byte
+
+func TestItem(t *testing.T) {
+	item := lex.NewItem(3, tokenIdent, []rune("lexing")...)
+
+	t.Run("Span", func(t *testing.T) {
+		if item.End() != 9 || item.Len() != 6 {
+			t.Errorf("unexpected end and length: wanted %d and %d ; got %d and %d", 9, 6, item.End(), item.Len())
+		}
+		if start, end := item.Span(); start != 3 || end != 9 {
+			t.Errorf("unexpected span: wanted [%d, %d) ; got [%d, %d)", 3, 9, start, end)
+		}
+	})
+
+	t.Run("Equal", func(t *testing.T) {
+		if !lex.Equal(item, lex.NewItem(3, tokenIdent, []rune("lexing")...)) {
+			t.Errorf("expected items to be equal")
+		}
+		for _, other := range []lex.Item[uint, rune]{
+			lex.NewItem(4, tokenIdent, []rune("lexing")...),
+			lex.NewItem(3, tokenPeriod, []rune("lexing")...),
+			lex.NewItem(3, tokenIdent, []rune("lexer")...),
+			{Pos: 3, Type: tokenIdent, Value: []rune("lexing"), Err: errors.New("error")},
+			{Pos: 3, Type: tokenIdent, Value: []rune("lexing"), Leading: []rune(" ")},
+			{Pos: 3, Type: tokenIdent, Value: []rune("lexing"), Channel: lex.HiddenChannel},
+		} {
+			if lex.Equal(item, other) {
+				t.Errorf("expected items to differ: %v", other)
+			}
+		}
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		if text := lex.Text(item); text != "lexing" {
+			t.Errorf("unexpected text: wanted %q ; got %q", "lexing", text)
+		}
+		if text := lex.Text(lex.NewItem(0, tokenIdent, []byte("héllo")...)); text != "héllo" {
+			t.Errorf("unexpected text: wanted %q ; got %q", "héllo", text)
+		}
+		if text := lex.Text(lex.NewItem(0, tokenIdent, []byteUnit{'h', 'i'}...)); text != "hi" {
+			t.Errorf("unexpected text: wanted %q ; got %q", "hi", text)
+		}
+	})
+
+	t.Run("Quote", func(t *testing.T) {
+		if quoted := item.Quote(); quoted != `"lexing"` {
+			t.Errorf("unexpected quoted value: wanted %q ; got %q", `"lexing"`, quoted)
+		}
+		if quoted := lex.NewItem(0, tokenIdent, []byteUnit{'h', '\t'}...).Quote(); quoted != `"h\t"` {
+			t.Errorf("unexpected quoted value: wanted %q ; got %q", `"h\t"`, quoted)
+		}
+		if quoted := lex.NewItem(0, tokenIdent, 1, 2).Quote(); quoted != `[1 2]` {
+			t.Errorf("unexpected quoted value: wanted %q ; got %q", `[1 2]`, quoted)
+		}
+	})
+
+	t.Run("String", func(t *testing.T) {
+		for _, test := range []struct {
+			item  fmt.Stringer
+			wants string
+		}{
+			{item, `2 "lexing" on position 3`},
+			{lex.NewItem(0, tokenIdent, []byte("a\n")...), `2 "a\n" on position 0`},
+			{lex.NewItem(1, tokenIdent, 1, 2), `2 [1 2] on position 1`},
+			{lex.Item[uint, rune]{Pos: 5, Err: lex.ErrMaxItems}, `0 "" on position 5: lexer exceeded its item count`},
+		} {
+			if got := fmt.Sprint(test.item); got != test.wants {
+				t.Errorf("unexpected output: wanted %q ; got %q", test.wants, got)
+			}
+		}
+	})
+}
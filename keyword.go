@@ -0,0 +1,164 @@
+package lex
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/zalgonoise/lex/class"
+)
+
+// CaseFold defines how a Keywords table compares identifiers against its keywords
+type CaseFold uint8
+
+const (
+	// FoldNone matches keywords with the exact same case (the default)
+	FoldNone CaseFold = iota
+	// FoldASCII matches keywords regardless of the case of their ASCII letters, like SQL keywords
+	FoldASCII
+	// FoldUnicode matches keywords under Unicode simple case folding, like `strings.EqualFold()`
+	FoldUnicode
+)
+
+// Keywords is a table of keywords, that promotes identifiers to their keyword's token type;
+// such as with its `Emit()` method, called from a StateFn in place of the lexer's `Emit()`
+//
+// Looking up an identifier does not allocate, as it is compared against the keywords with the
+// same number of runes, decoding UTF-8 text in byte lexers on the fly. A Keywords is read-only
+// once configured, so it can be shared across lexers
+type Keywords[C comparable, T class.Char] struct {
+	ident C
+	fold  CaseFold
+	byLen [][]keyword[C]
+}
+
+// keyword is an entry in a Keywords table
+type keyword[C comparable] struct {
+	runes []rune
+	token C
+}
+
+// NewKeywords creates a Keywords table from the keywords `keywords` mapped to their token types,
+// emitting other identifiers with the token type `ident`
+func NewKeywords[C comparable, T class.Char](ident C, keywords map[string]C) *Keywords[C, T] {
+	k := &Keywords[C, T]{
+		ident: ident,
+	}
+	for word, token := range keywords {
+		runes := []rune(word)
+		for len(k.byLen) <= len(runes) {
+			k.byLen = append(k.byLen, nil)
+		}
+		k.byLen[len(runes)] = append(k.byLen[len(runes)], keyword[C]{runes: runes, token: token})
+	}
+	return k
+}
+
+// Fold sets the CaseFold used to compare identifiers against the keywords
+//
+// If several keywords match the same identifier under the CaseFold (like `Select` and `SELECT`
+// with FoldASCII), which of them is matched is unspecified
+func (k *Keywords[C, T]) Fold(fold CaseFold) {
+	k.fold = fold
+}
+
+// Lookup returns the token type of the keyword matching the units `units`, and true; or the
+// identifier token type and false if it is not a keyword
+func (k *Keywords[C, T]) Lookup(units []T) (C, bool) {
+	count, ok := k.count(units)
+	if !ok {
+		return k.ident, false
+	}
+	for _, kw := range k.byLen[count] {
+		if k.match(units, kw.runes) {
+			return kw.token, true
+		}
+	}
+	return k.ident, false
+}
+
+// Emit pushes the units from the lexer's starting index to the current position index, as an
+// item of its keyword's token type if it is a keyword, or as an identifier item otherwise;
+// returning the emitted token type
+func (k *Keywords[C, T]) Emit(l Lexer[C, T]) C {
+	itemType, _ := k.Lookup(l.Extract(l.Start(), l.Pos()))
+	l.Emit(itemType)
+	return itemType
+}
+
+// count returns the number of runes in the units `units`, and false if it is longer than any of
+// the keywords
+func (k *Keywords[C, T]) count(units []T) (int, bool) {
+	var count int
+	for idx := 0; idx < len(units); count++ {
+		if count >= len(k.byLen)-1 {
+			return 0, false
+		}
+		_, size := decodeUnit(units[idx:])
+		idx += size
+	}
+	return count, count < len(k.byLen)
+}
+
+// match returns true if the units `units` match the keyword `runes`, under the Keywords' CaseFold
+func (k *Keywords[C, T]) match(units []T, runes []rune) bool {
+	var idx int
+	for _, kr := range runes {
+		r, size := decodeUnit(units[idx:])
+		idx += size
+		if r == kr {
+			continue
+		}
+		switch k.fold {
+		case FoldASCII:
+			if lowerASCII(r) != lowerASCII(kr) {
+				return false
+			}
+		case FoldUnicode:
+			if !equalFold(r, kr) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// decodeUnit returns the first rune in the units `units` and the number of units it spans:
+// decoding UTF-8 text for byte units, or reading a single rune for rune units
+func decodeUnit[T class.Char](units []T) (rune, int) {
+	var zero T
+	if ^zero < 0 {
+		// signed: a rune type
+		return rune(units[0]), 1
+	}
+	if units[0] < utf8.RuneSelf {
+		return rune(units[0]), 1
+	}
+	var (
+		buf [utf8.UTFMax]byte
+		n   int
+	)
+	for ; n < len(buf) && n < len(units); n++ {
+		buf[n] = byte(units[n])
+	}
+	return utf8.DecodeRune(buf[:n])
+}
+
+// lowerASCII returns the lowercase form of the rune `r`, if it is an ASCII letter
+func lowerASCII(r rune) rune {
+	if r >= 'A' && r <= 'Z' {
+		return r + 'a' - 'A'
+	}
+	return r
+}
+
+// equalFold returns true if the runes `a` and `b` are equal under Unicode simple case folding
+func equalFold(a, b rune) bool {
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package lex_test
+
+import (
+	"testing"
+
+	"github.com/zalgonoise/lex"
+)
+
+var keywordTable = map[string]uint{
+	"select": tokenSelect,
+	"from":   tokenFrom,
+	"été":    tokenEte,
+	"kind":   tokenKind,
+}
+
+// scanWord returns a scan fragment that emits a space-separated word with the Keywords `k`, as
+// a keyword or ident item
+func scanWord[T rune | byte](k *lex.Keywords[uint, T]) func(l lex.Lexer[uint, T]) bool {
+	return func(l lex.Lexer[uint, T]) bool {
+		l.AcceptRun(func(item T) bool { return item != ' ' && item != '\n' && item != 0 })
+		k.Emit(l)
+		return true
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		fold  lex.CaseFold
+		input string
+		wants []uint
+	}{
+		{"Exact", lex.FoldNone, "select a from été", []uint{tokenSelect, tokenIdent, tokenFrom, tokenEte}},
+		{"ExactCase", lex.FoldNone, "SELECT From ÉTÉ", []uint{tokenIdent, tokenIdent, tokenIdent}},
+		{"Prefix", lex.FoldNone, "sel selection fro", []uint{tokenIdent, tokenIdent, tokenIdent}},
+		{"ASCII", lex.FoldASCII, "SELECT From ÉTÉ éTé", []uint{tokenSelect, tokenFrom, tokenIdent, tokenEte}},
+		{"Unicode", lex.FoldUnicode, "SeLeCt ÉTÉ \u212Aind", []uint{tokenSelect, tokenEte, tokenKind}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			verify := func(t *testing.T, types []uint) {
+				// drop the EOF item
+				types = types[:len(types)-1]
+				if len(types) != len(test.wants) {
+					t.Errorf("unexpected items: wanted %v ; got %v", test.wants, types)
+					return
+				}
+				for idx := range test.wants {
+					if types[idx] != test.wants[idx] {
+						t.Errorf("unexpected token #%d: wanted %d ; got %d", idx, test.wants[idx], types[idx])
+					}
+				}
+			}
+
+			t.Run("Runes", func(t *testing.T) {
+				k := lex.NewKeywords[uint, rune](tokenIdent, keywordTable)
+				k.Fold(test.fold)
+				var types []uint
+				for _, item := range lex.Collect[uint, rune](lex.New(scanState(scanWord(k)), []rune(test.input))) {
+					types = append(types, item.Type)
+				}
+				verify(t, types)
+			})
+			t.Run("Bytes", func(t *testing.T) {
+				k := lex.NewKeywords[uint, byte](tokenIdent, keywordTable)
+				k.Fold(test.fold)
+				var types []uint
+				for _, item := range lex.Collect[uint, byte](lex.New(scanState(scanWord(k)), []byte(test.input))) {
+					types = append(types, item.Type)
+				}
+				verify(t, types)
+			})
+		})
+	}
+}
+
+func TestKeywordsLookup(t *testing.T) {
+	k := lex.NewKeywords[uint, byte](tokenIdent, keywordTable)
+	k.Fold(lex.FoldUnicode)
+
+	if token, ok := k.Lookup([]byte("FROM")); token != tokenFrom || !ok {
+		t.Errorf("unexpected lookup: wanted %d %v ; got %d %v", tokenFrom, true, token, ok)
+	}
+	if token, ok := k.Lookup(nil); token != tokenIdent || ok {
+		t.Errorf("unexpected lookup: wanted %d %v ; got %d %v", tokenIdent, false, token, ok)
+	}
+	if token, ok := lex.NewKeywords[uint, byte](tokenIdent, nil).Lookup([]byte("from")); token != tokenIdent || ok {
+		t.Errorf("unexpected lookup: wanted %d %v ; got %d %v", tokenIdent, false, token, ok)
+	}
+
+	input := []byte("ÉTÉ")
+	if allocs := testing.AllocsPerRun(100, func() { k.Lookup(input) }); allocs != 0 {
+		t.Errorf("unexpected allocations: wanted %d ; got %v", 0, allocs)
+	}
+}
@@ -1,6 +1,8 @@
 package lex
 
 import (
+	"fmt"
+
 	cur "github.com/zalgonoise/cur"
 )
 
@@ -11,6 +13,10 @@ import (
 //
 // Its `Emit()` method pushes items into the stack to be returned, and its `Accept()`,
 // `Check()` and `AcceptRun()` methods act as verifiers for a (set of) token(s)
+//
+// Further capabilities are described by separate interfaces, that a StateFn can assert its
+// Lexer to: ChannelEmitter, RangeEmitter, ValueEmitter, HintReader, Nester and Acceptor. Both
+// Lex and LexBuffer implement all of them
 type Lexer[C comparable, T any] interface {
 
 	// Cursor navigates through a slice in a controlled manner, allowing the
@@ -59,14 +65,15 @@ type Lexer[C comparable, T any] interface {
 	//
 	// If the validation passes, the cursor has moved one step forward (the unit was consumed)
 	//
-	// If the validation fails, the cursor rolls back one step
+	// If the validation fails, the cursor rolls back one step; and if the cursor is already at the
+	// end of the input, nothing is consumed and false is returned
 	Accept(verifFn func(item T) bool) bool
 
 	// AcceptRun iterates through all following tokens, passing them through the input `verifFn`
 	// function as a validator
 	//
 	// Once it fails the verification, the cursor is rolledback once, leaving the caller at the unit
-	// that failed the verifFn
+	// that failed the verifFn; or it stops at the end of the input
 	AcceptRun(verifFn func(item T) bool)
 }
 
@@ -89,23 +96,88 @@ type Lex[C comparable, T any] struct {
 	pos   int
 	state StateFn[C, T]
 	items chan Item[C, T]
+
+	emitted  int
+	guard    guard
+	fault    *Item[C, T]
+	recovery bool
+	trivia   trivia[C, T]
+	channels channels[C, T]
+	init     StateFn[C, T]
+	hint     any
+	eof      T
+	tracer   Tracer[C, T]
 }
 
 var _ Lexer[uint8, any] = &Lex[uint8, any]{}
 
 // New creates a new lexer with the base / starting StateFn and input data
+//
+// An empty input lexes to an EOF item. To configure the lexer with Options, use NewLex
 func New[C comparable, T any](
 	initFn StateFn[C, T],
 	input []T,
 ) *Lex[C, T] {
+	l, _ := NewLex(initFn, input)
+	return l
+}
+
+// NewLex creates a new lexer with the base / starting StateFn and input data, configured with
+// the input Options `opts`
+//
+// An empty input lexes to an EOF item. It returns an error if any of the Options is invalid
+func NewLex[C comparable, T any](
+	initFn StateFn[C, T],
+	input []T,
+	opts ...Option,
+) (*Lex[C, T], error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	eof, tracer, err := typed[C, T](o)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Lex[C, T]{
+		input:    input,
+		state:    initFn,
+		init:     initFn,
+		items:    make(chan Item[C, T], o.queueSize),
+		guard:    guard{limits: o.limits},
+		recovery: o.recovery,
+		eof:      eof,
+		tracer:   tracer,
+	}
 	if len(input) == 0 {
-		return nil
+		l.state = nil
 	}
+	return l, nil
+}
 
-	return &Lex[C, T]{
-		input: input,
-		state: initFn,
-		items: make(chan Item[C, T], 2),
+// Reset prepares the lexer to lex the input data `input` from the start, with the base /
+// starting StateFn `initFn`; reusing its items queue, so that lexing many small inputs does
+// not allocate a new lexer for each of them
+//
+// The lexer's configuration (its Options, Limits, TriviaMode and channel routes) is kept, while
+// its state (the queued and hidden items, the pending trivia, the hint and the counted StateFn
+// transitions) is discarded. An empty input lexes to an EOF item
+func (l *Lex[C, T]) Reset(initFn StateFn[C, T], input []T) {
+	drain(l.items)
+	l.input = input
+	l.start = 0
+	l.pos = 0
+	l.state = initFn
+	l.init = initFn
+	l.emitted = 0
+	l.guard.reset()
+	l.fault = nil
+	l.trivia.reset(nil)
+	l.channels.reset()
+	l.hint = nil
+	if len(input) == 0 {
+		l.state = nil
 	}
 }
 
@@ -117,22 +189,173 @@ func New[C comparable, T any](
 // Note that multiple calls to `NextItem()` should be made when tokenizing input data;
 // usually in a for-loop while the output item is not EOF.
 func (l *Lex[C, T]) NextItem() Item[C, T] {
-	var next Item[C, T]
+	item := l.next()
+	if l.tracer != nil {
+		l.tracer.Item(item)
+	}
+	return item
+}
+
+// next returns the next queued item, running the StateFns until one is emitted
+func (l *Lex[C, T]) next() Item[C, T] {
 	for {
 		select {
-		case next = <-l.items:
+		case next := <-l.items:
 			return next
 		default:
 			if l.state != nil {
-				l.state = l.state(l)
+				l.step()
 				continue
 			}
-			close(l.items)
-			return next
+			if item, ok := l.trivia.flush(); ok {
+				return item
+			}
+			if l.fault != nil {
+				fault := *l.fault
+				l.fault = nil
+				return l.trivia.eof(fault)
+			}
+			// no more StateFns to run: return an EOF item on the cursor's position
+			return l.trivia.eof(Item[C, T]{Pos: l.pos})
 		}
 	}
 }
 
+// step runs the current StateFn, verifying the lexer's Limits before and after the transition
+//
+// If a limit is exceeded (or a panic is recovered), the lexer is stopped with a diagnostic EOF
+// item carrying a *StateError
+func (l *Lex[C, T]) step() {
+	if err := l.guard.before(); err != nil {
+		l.stop(err, l.state)
+		return
+	}
+
+	state := l.state
+	if l.tracer != nil {
+		l.tracer.State(stateName(state), l.pos)
+	}
+	next, err := run[C, T](l, state, l.recovery)
+	if err != nil {
+		l.stop(err, state)
+		return
+	}
+	l.state = next
+
+	if err := l.guard.after(l.start, l.pos, l.emitted); err != nil {
+		l.stop(err, state)
+	}
+}
+
+// stop halts the lexer, setting up a diagnostic EOF item with the error `err`, raised
+// in the StateFn `state`, positioned on the lexer's starting point
+func (l *Lex[C, T]) stop(err error, state StateFn[C, T]) {
+	l.state = nil
+	l.fault = &Item[C, T]{
+		Pos: l.start,
+		Err: &StateError{
+			Err:   err,
+			State: stateName(state),
+			Pos:   l.pos,
+		},
+	}
+}
+
+// Limit sets the runtime limits for the lexer, that stop it with a diagnostic EOF item
+// (carrying a *StateError) when exceeded
+func (l *Lex[C, T]) Limit(limits Limits) {
+	l.guard.limits = limits
+}
+
+// Recover enables or disables the recovery of panics raised by the StateFns
+//
+// When enabled, a panic stops the lexer with a diagnostic EOF item carrying a *StateError that
+// wraps a *PanicError, with the recovered value and stack trace
+func (l *Lex[C, T]) Recover(enabled bool) {
+	l.recovery = enabled
+}
+
+// Trivia sets the lexer's TriviaMode, defining whether the units skipped with `Ignore()` are
+// discarded or attached to the emitted items (as Leading or Trailing trivia). The values of the
+// items emitted on a hidden channel are attached as trivia too
+func (l *Lex[C, T]) Trivia(mode TriviaMode) {
+	l.trivia.mode = mode
+}
+
+// Route sets the default channel for the items of token type `itemType`, when emitted with
+// `Emit()`
+func (l *Lex[C, T]) Route(itemType C, channel Channel) {
+	l.channels.set(itemType, channel)
+}
+
+// Hidden returns the items emitted on channels other than the DefaultChannel so far, in the
+// order they were emitted; or only the ones on the input channels `chs`, if any are provided
+func (l *Lex[C, T]) Hidden(chs ...Channel) []Item[C, T] {
+	return l.channels.items(chs...)
+}
+
+// Hint returns the hint set by the lexer's consumer (such as a parser), to lex
+// context-sensitive tokens; or nil if none is set
+func (l *Lex[C, T]) Hint() any {
+	return l.hint
+}
+
+// Enter marks the start of a nesting level (such as an opening bracket), returning true
+//
+// If the lexer's MaxDepth limit is exceeded, it returns false, and the lexer is stopped once
+// the current StateFn returns, with a diagnostic EOF item wrapping ErrMaxDepth
+func (l *Lex[C, T]) Enter() bool {
+	return l.guard.enter() == nil
+}
+
+// Leave marks the end of a nesting level, entered with `Enter()`
+func (l *Lex[C, T]) Leave() {
+	l.guard.leave()
+}
+
+// Depth returns the current nesting level, as set with `Enter()` and `Leave()`
+func (l *Lex[C, T]) Depth() int {
+	return l.guard.depth
+}
+
+// SetHint sets the hint `hint` for the lexer's StateFns to read with `Hint()`, allowing the
+// lexer's consumer (such as a parser) to provide context for context-sensitive tokens, like
+// `/` as a division operator versus the start of a regular expression
+//
+// The hint is kept until it is set again, and is only read by the StateFns that run after it
+// is set. Items already queued by the lexer can be lexed again with `Relex()`
+func (l *Lex[C, T]) SetHint(hint any) {
+	l.hint = hint
+}
+
+// Relex rewinds the lexer to the position of the item `item` (usually, the last item returned
+// by `NextItem()`), so that its units are lexed again with the StateFn `state`, or the lexer's
+// starting StateFn if nil; such as after setting a new hint with `SetHint()`
+//
+// Any items emitted after `item` are discarded, and any lookahead items held by the consumer
+// must be discarded too. If the lexer no longer holds the units for `item`, an error wrapping
+// ErrRelex is returned
+func (l *Lex[C, T]) Relex(item Item[C, T], state StateFn[C, T]) error {
+	if item.Pos < 0 || item.Pos > l.pos {
+		return fmt.Errorf("%w: position %d is out of range", ErrRelex, item.Pos)
+	}
+	idx := item.Pos
+
+	if state == nil {
+		state = l.init
+	}
+
+	drain(l.items)
+	l.trivia.reset(item.Leading)
+	l.channels.truncate(item.Pos)
+	l.fault = nil
+	l.state = state
+	l.start = idx
+	l.pos = idx
+	l.guard.rewind(idx)
+	return nil
+}
+
 // Emit pushes the set of units identified by token `itemType` to the items channel,
 // that returns it in the NextItem() method.
 //
@@ -140,17 +363,150 @@ func (l *Lex[C, T]) NextItem() Item[C, T] {
 // starting index to the current position index.
 //
 // It also sets the lexer's starting index to the current position index.
+//
+// The item is pushed to the token type's default channel, as set with `Route()`; which is the
+// DefaultChannel unless set otherwise
 func (l *Lex[C, T]) Emit(itemType C) {
-	l.items <- Item[C, T]{
-		Pos:   l.start,
-		Type:  itemType,
-		Value: l.input[l.start:l.pos],
+	l.EmitOn(l.channels.route(itemType), itemType)
+}
+
+// EmitOn works like `Emit()`, but pushes the item to the channel `channel` instead of the
+// token type's default channel
+//
+// Items on a channel other than the DefaultChannel are not returned by `NextItem()`, but are
+// kept aside, and can be retrieved with the `Hidden()` method
+func (l *Lex[C, T]) EmitOn(channel Channel, itemType C) {
+	l.emitted++
+	if !l.push(Item[C, T]{
+		Pos:     l.start,
+		Type:    itemType,
+		Value:   l.input[l.start:l.pos],
+		Channel: channel,
+	}, l.pos-l.start) {
+		return
+	}
+	l.start = l.pos
+}
+
+// EmitRange pushes the units from index `start` to index `end` (in the same reference as
+// `Extract()`), identified by token `itemType`, positioned on index `start`
+//
+// The indices are clamped to the bounds of the input, as with `Extract()`. It does not change
+// the lexer's starting point nor its position
+func (l *Lex[C, T]) EmitRange(itemType C, start, end int) {
+	l.emitted++
+	if start < 0 {
+		start = 0
+	}
+	if start > len(l.input) {
+		start = len(l.input)
+	}
+	if end > len(l.input) {
+		end = len(l.input)
+	}
+	if end < start {
+		end = start
+	}
+	_ = l.push(Item[C, T]{
+		Pos:     start,
+		Type:    itemType,
+		Value:   l.input[start:end],
+		Channel: l.channels.route(itemType),
+	}, end-start)
+}
+
+// EmitEmpty pushes a zero-width item identified by token `itemType`, positioned on the
+// lexer's starting point; such as an inserted separator, or an INDENT / DEDENT token
+//
+// It does not change the lexer's starting point nor its position
+func (l *Lex[C, T]) EmitEmpty(itemType C) {
+	l.emitted++
+	_ = l.push(Item[C, T]{
+		Pos:     l.start,
+		Type:    itemType,
+		Channel: l.channels.route(itemType),
+	}, 0)
+}
+
+// EmitValue pushes an item identified by token `itemType` with the value `value`, positioned
+// on the lexer's starting point; such as a string literal with its escape sequences decoded
+//
+// Like `Emit()`, it consumes the units from the lexer's starting index to the current
+// position index, setting the starting index to the current position index
+func (l *Lex[C, T]) EmitValue(itemType C, value []T) {
+	l.emitted++
+	if !l.push(Item[C, T]{
+		Pos:     l.start,
+		Type:    itemType,
+		Value:   value,
+		Channel: l.channels.route(itemType),
+	}, l.pos-l.start) {
+		return
+	}
+	l.start = l.pos
+}
+
+// EmitData works like `Emit()`, but also sets the item's Data to `data`; such as a number
+// parsed from the item's units, or a keyword ID
+//
+// The data travels with the item through any Emitter (and into a parse.Tree's nodes), and can
+// be read as a typed value with the Data function
+func (l *Lex[C, T]) EmitData(itemType C, data any) {
+	l.emitted++
+	if !l.push(Item[C, T]{
+		Pos:     l.start,
+		Type:    itemType,
+		Value:   l.input[l.start:l.pos],
+		Channel: l.channels.route(itemType),
+		Data:    data,
+	}, l.pos-l.start) {
+		return
 	}
 	l.start = l.pos
 }
 
+// EmitError pushes the set of units from the lexer's starting index to the current position
+// index, identified by token `itemType` and carrying the error `err`
+//
+// Like `Emit()`, it sets the lexer's starting index to the current position index
+func (l *Lex[C, T]) EmitError(itemType C, err error) {
+	l.emitted++
+	if !l.push(Item[C, T]{
+		Pos:     l.start,
+		Type:    itemType,
+		Value:   l.input[l.start:l.pos],
+		Err:     err,
+		Channel: l.channels.route(itemType),
+	}, l.pos-l.start) {
+		return
+	}
+	l.start = l.pos
+}
+
+// push sends the item `item` to its channel: either to the items channel, returned in the
+// NextItem() method, or kept aside as a hidden item
+//
+// It returns false if the item was dropped, for spanning over `width` units of the input exceeding
+// the lexer's MaxWidth, or for exceeding its MaxItems; which stops the lexer once the current
+// StateFn returns, with a diagnostic EOF item positioned on the dropped item
+func (l *Lex[C, T]) push(item Item[C, T], width int) bool {
+	if l.guard.item(width) != nil {
+		return false
+	}
+	if item.Channel != DefaultChannel {
+		l.channels.hidden = append(l.channels.hidden, item)
+		l.trivia.hide(item)
+		return true
+	}
+	if item, ok := l.trivia.attach(item); ok {
+		l.items <- item
+	}
+	return true
+}
+
 // Ignore will set the starting point as the current position, ignoring any preceeding units
 func (l *Lex[C, T]) Ignore() {
+	l.trivia.ignore(l.input[l.start:l.pos])
 	l.start = l.pos
 }
 
@@ -180,9 +536,16 @@ func (l *Lex[C, T]) Check(verifFn func(item T) bool) bool {
 //
 // If the validation passes, the cursor has moved one step forward (the unit was consumed)
 //
-// If the validation fails, the cursor rolls back one step
+// If the validation fails, the cursor rolls back one step; and if the cursor is already at the
+// end of the input, nothing is consumed and false is returned
 func (l *Lex[C, T]) Accept(verifFn func(item T) bool) bool {
-	if ok := verifFn(l.Next()); ok {
+	pos := l.pos
+	ok := verifFn(l.Next())
+	if l.pos == pos {
+		// EOF: nothing was consumed
+		return false
+	}
+	if ok {
 		return true
 	}
 	l.Prev()
@@ -193,11 +556,58 @@ func (l *Lex[C, T]) Accept(verifFn func(item T) bool) bool {
 // function as a validator
 //
 // Once it fails the verification, the cursor is rolledback once, leaving the caller at the unit
-// that failed the verifFn
+// that failed the verifFn; or it stops at the end of the input
 func (l *Lex[C, T]) AcceptRun(verifFn func(item T) bool) {
-	for verifFn(l.Next()) {
+	for {
+		pos := l.pos
+		ok := verifFn(l.Next())
+		if l.pos == pos {
+			// EOF: nothing was consumed
+			return
+		}
+		if !ok {
+			l.Prev()
+			return
+		}
 	}
-	l.Prev()
+}
+
+// AcceptUntil consumes all units up to (but not including) the first unit that passes the
+// `verifFn` validator, returning true
+//
+// If the end of the input is reached first, the cursor is rolled back to where it was,
+// and false is returned
+func (l *Lex[C, T]) AcceptUntil(verifFn func(item T) bool) bool {
+	pos := l.pos
+	for {
+		next := l.pos
+		item := l.Next()
+		if l.pos == next {
+			// EOF
+			l.pos = pos
+			return false
+		}
+		if verifFn(item) {
+			l.pos = next
+			return true
+		}
+	}
+}
+
+// AcceptN consumes exactly `n` units that pass the `verifFn` validator, returning true
+//
+// If any of them fails the validation (or the end of the input is reached), the cursor is
+// rolled back to where it was, and false is returned
+func (l *Lex[C, T]) AcceptN(n int, verifFn func(item T) bool) bool {
+	pos := l.pos
+	for i := 0; i < n; i++ {
+		next := l.pos
+		if item := l.Next(); l.pos == next || !verifFn(item) {
+			l.pos = pos
+			return false
+		}
+	}
+	return true
 }
 
 // Cur returns the same indexed item in the slice
@@ -205,8 +615,7 @@ func (l *Lex[C, T]) AcceptRun(verifFn func(item T) bool) {
 // If the position is already over the size of the input, the zero-value EOF token is returned
 func (l *Lex[C, T]) Cur() T {
 	if l.pos >= len(l.input) {
-		var eof T
-		return eof
+		return l.eof
 	}
 	return l.input[l.pos]
 }
@@ -228,8 +637,7 @@ func (l *Lex[C, T]) Len() int {
 // value is NOT incremented and the zero-value EOF token is returned
 func (l *Lex[C, T]) Next() T {
 	if l.pos >= len(l.input) {
-		var eof T
-		return eof
+		return l.eof
 	}
 	l.pos++
 	return l.input[l.pos-1]
@@ -242,8 +650,7 @@ func (l *Lex[C, T]) Next() T {
 // and the zero-value EOF token is returned
 func (l *Lex[C, T]) Prev() T {
 	if l.pos-1 < 0 {
-		var eof T
-		return eof
+		return l.eof
 	}
 	l.pos--
 	for l.pos < l.start {
@@ -257,8 +664,7 @@ func (l *Lex[C, T]) Prev() T {
 // If the next token overflows the input's index, the zero-value EOF token is returned
 func (l *Lex[C, T]) Peek() T {
 	if l.pos+1 >= len(l.input) {
-		var eof T
-		return eof
+		return l.eof
 	}
 	return l.input[l.pos+1]
 }
@@ -268,12 +674,19 @@ func (l *Lex[C, T]) Peek() T {
 func (l *Lex[C, T]) Head() T {
 	l.pos = 0
 	l.start = 0
+	if len(l.input) == 0 {
+		return l.eof
+	}
 	return l.input[l.pos]
 }
 
 // Tail jumps to the end of the slice, setting both lexer's start and position values to
 // the last item in the input
 func (l *Lex[C, T]) Tail() T {
+	if len(l.input) == 0 {
+		return l.eof
+	}
+
 	l.pos = len(l.input) - 1
 	l.start = len(l.input) - 1
 	return l.input[l.pos]
@@ -286,12 +699,10 @@ func (l *Lex[C, T]) Tail() T {
 // zero-value EOF token is returned
 func (l *Lex[C, T]) Idx(idx int) T {
 	if idx < 0 {
-		var eof T
-		return eof
+		return l.eof
 	}
 	if idx >= len(l.input) {
-		var eof T
-		return eof
+		return l.eof
 	}
 	l.pos = idx
 	if idx < l.start {
@@ -308,12 +719,10 @@ func (l *Lex[C, T]) Idx(idx int) T {
 // zero-value EOF token is returned
 func (l *Lex[C, T]) Offset(amount int) T {
 	if l.pos+amount < 0 {
-		var eof T
-		return eof
+		return l.eof
 	}
 	if l.pos+amount >= len(l.input) {
-		var eof T
-		return eof
+		return l.eof
 	}
 	l.pos += amount
 	if l.pos-l.start < 0 {
@@ -330,12 +739,10 @@ func (l *Lex[C, T]) Offset(amount int) T {
 // zero-value EOF token is returned
 func (l *Lex[C, T]) PeekIdx(idx int) T {
 	if idx >= len(l.input) {
-		var eof T
-		return eof
+		return l.eof
 	}
 	if idx < 0 {
-		var eof T
-		return eof
+		return l.eof
 	}
 	return l.input[idx]
 }
@@ -348,8 +755,7 @@ func (l *Lex[C, T]) PeekIdx(idx int) T {
 // zero-value EOF token is returned
 func (l *Lex[C, T]) PeekOffset(amount int) T {
 	if l.pos+amount >= len(l.input) || l.pos+amount < 0 {
-		var eof T
-		return eof
+		return l.eof
 	}
 	return l.input[l.pos+amount]
 }
@@ -1,17 +1,14 @@
 package lex_test
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/zalgonoise/gbuf"
+	"github.com/zalgonoise/gio"
 	"github.com/zalgonoise/lex"
-)
-
-const (
-	tokenEOF uint = iota
-	tokenError
-	tokenIdent
-	tokenPeriod
+	"github.com/zalgonoise/lex/lextest"
 )
 
 var (
@@ -117,6 +114,32 @@ func TestLexer(t *testing.T) {
 	}
 }
 
+func TestLexerCases(t *testing.T) {
+	lextest.Run(t, initState[uint, rune],
+		lextest.Case[uint, rune]{
+			Name:  "Periods",
+			Input: testInput2,
+			Items: []lex.Item[uint, rune]{
+				lex.NewItem(0, tokenIdent, []rune("lexing")...),
+				lex.NewItem(6, tokenPeriod, '.'),
+				lex.NewItem(7, tokenIdent, []rune("data")...),
+				lex.NewItem(11, tokenPeriod, '.'),
+				lex.NewItem[uint, rune](12, tokenEOF),
+			},
+		},
+		lextest.Case[uint, rune]{
+			Name:  "LeadingPeriod",
+			Input: testInput3,
+			Items: []lex.Item[uint, rune]{
+				lex.NewItem(0, tokenPeriod, '.'),
+				lex.NewItem(1, tokenIdent, []rune("lexing data")...),
+				lex.NewItem(12, tokenPeriod, '.'),
+				lex.NewItem[uint, rune](13, tokenEOF),
+			},
+		},
+	)
+}
+
 func TestNextItem(t *testing.T) {
 	t.Run("LastChar", func(t *testing.T) {
 		wants := "lexing data"
@@ -447,3 +470,289 @@ func TestAcceptanceMethods(t *testing.T) {
 		t.Errorf("unexpected token type: wanted %s ; got %s", wantsPeriod, string(i.Value))
 	}
 }
+
+func TestLexBuffer(t *testing.T) {
+	for _, input := range [][]rune{testInput1, testInput2, testInput3} {
+		lextest.Check(t, initState[uint, rune], input)
+		lextest.Check(t, acceptanceState[uint, rune], input)
+	}
+}
+
+// stuckState describes a StateFn that never moves the cursor nor emits any items
+func stuckState[C uint, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	l.Next()
+	l.Prev()
+	return stuckState[C, T]
+}
+
+// forthState and backState describe a pair of StateFns that move the cursor forward and back
+// over the same unit, without emitting any items
+func forthState[C uint, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	l.Next()
+	return backState[C, T]
+}
+
+func backState[C uint, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	l.Prev()
+	return forthState[C, T]
+}
+
+func TestLimits(t *testing.T) {
+	t.Run("NoProgress", func(t *testing.T) {
+		l := lex.New(stuckState[uint, rune], testInput1)
+
+		i := l.NextItem()
+		if i.Type != tokenEOF {
+			t.Errorf("unexpected token type: wanted %d ; got %d", tokenEOF, i.Type)
+		}
+		if !errors.Is(i.Err, lex.ErrNoProgress) {
+			t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrNoProgress, i.Err)
+		}
+		var stateErr *lex.StateError
+		if !errors.As(i.Err, &stateErr) || !strings.Contains(stateErr.State, "stuckState") {
+			t.Errorf("expected error to name the stuck StateFn ; got %v", i.Err)
+		}
+		if i = l.NextItem(); i.Type != tokenEOF || i.Err != nil {
+			t.Errorf("unexpected item after the diagnostic item: %v", i)
+		}
+	})
+
+	t.Run("Oscillation", func(t *testing.T) {
+		b := lex.NewBuffer(forthState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(testInput1)))
+		for _, e := range []lex.Emitter[uint, rune]{lex.New(forthState[uint, rune], testInput1), b} {
+			i := e.NextItem()
+			if !errors.Is(i.Err, lex.ErrNoProgress) {
+				t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrNoProgress, i.Err)
+			}
+			if i.Pos != 0 {
+				t.Errorf("unexpected diagnostic item position: wanted %d ; got %d", 0, i.Pos)
+			}
+		}
+	})
+
+	t.Run("MaxSteps", func(t *testing.T) {
+		l := lex.NewBuffer(initState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(testInput2)))
+		l.Limit(lex.Limits{MaxSteps: 2})
+
+		items := lex.Collect[uint, rune](l)
+		if len(items) != 3 {
+			t.Errorf("token slice length mismatch error: wanted %d ; got %d", 3, len(items))
+			return
+		}
+		if !errors.Is(items[2].Err, lex.ErrMaxSteps) {
+			t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrMaxSteps, items[2].Err)
+		}
+		if items[2].Pos != 7 {
+			t.Errorf("unexpected diagnostic item position: wanted %d ; got %d", 7, items[2].Pos)
+		}
+	})
+}
+
+// nestState describes a StateFn that emits space-separated words as ident items, and
+// parentheses as period items, entering and leaving a nesting level on each of them
+func nestState[C uint, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	l.AcceptRun(func(item T) bool { return item == ' ' })
+	l.Ignore()
+
+	switch l.Cur() {
+	case 0:
+		l.Emit((C)(tokenEOF))
+		return nil
+	case '(':
+		if !l.(lex.Nester).Enter() {
+			return nil
+		}
+		l.Next()
+		l.Emit((C)(tokenPeriod))
+	case ')':
+		l.(lex.Nester).Leave()
+		l.Next()
+		l.Emit((C)(tokenPeriod))
+	default:
+		l.AcceptRun(func(item T) bool { return item != ' ' && item != '(' && item != ')' && item != 0 })
+		l.Emit((C)(tokenIdent))
+	}
+	return nestState[C, T]
+}
+
+// endlessReader is a gio.Reader that never reaches the end of its input
+type endlessReader struct{}
+
+func (endlessReader) Read(b []rune) (int, error) {
+	for i := range b {
+		b[i] = 'a'
+	}
+	return len(b), nil
+}
+
+func TestResourceLimits(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		limits lex.Limits
+		input  string
+		items  int
+		pos    int
+		err    error
+	}{
+		{"MaxWidth", lex.Limits{MaxWidth: 4}, "ab cdefghij", 2, 3, lex.ErrMaxWidth},
+		{"MaxWidthFits", lex.Limits{MaxWidth: 4}, "ab cdef", 3, 0, nil},
+		{"MaxItems", lex.Limits{MaxItems: 2}, "ab cd ef", 3, 6, lex.ErrMaxItems},
+		{"MaxItemsFits", lex.Limits{MaxItems: 4}, "ab cd ef", 4, 0, nil},
+		{"MaxDepth", lex.Limits{MaxDepth: 1}, "(a (b))", 3, 3, lex.ErrMaxDepth},
+		{"MaxDepthFits", lex.Limits{MaxDepth: 2}, "(a (b))", 7, 0, nil},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			input := []rune(test.input)
+			l, err := lex.NewLex(nestState[uint, rune], input, lex.WithLimits(test.limits))
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			buffer, err := lex.NewLexBuffer(nestState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(input)), lex.WithLimits(test.limits))
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			for _, items := range [][]lex.Item[uint, rune]{lex.Collect[uint, rune](l), lex.Collect[uint, rune](buffer)} {
+				if len(items) != test.items {
+					t.Errorf("token slice length mismatch error: wanted %d ; got %d", test.items, len(items))
+					return
+				}
+				if last := items[len(items)-1]; last.Type != tokenEOF || !errors.Is(last.Err, test.err) || (test.err == nil) != (last.Err == nil) {
+					t.Errorf("unexpected EOF item: wanted error %v ; got %v", test.err, last)
+				}
+				if test.err != nil && items[len(items)-1].Pos != test.pos {
+					t.Errorf("unexpected diagnostic item position: wanted %d ; got %d", test.pos, items[len(items)-1].Pos)
+				}
+			}
+		})
+	}
+
+	t.Run("EndlessInput", func(t *testing.T) {
+		for _, test := range []struct {
+			name   string
+			limits lex.Limits
+			size   int
+			err    error
+		}{
+			// reading one unit past MaxWidth, to find the end of the item
+			{"MaxWidth", lex.Limits{MaxWidth: 64}, 65, lex.ErrMaxWidth},
+			{"MaxBuffered", lex.Limits{MaxBuffered: 64}, 64, lex.ErrMaxBuffered},
+		} {
+			t.Run(test.name, func(t *testing.T) {
+				l, err := lex.NewLexBuffer(nestState[uint, rune], gio.Reader[rune](endlessReader{}), lex.WithLimits(test.limits))
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+
+				// the never-ending word is not emitted, and the lexer stops with a diagnostic item
+				item := l.NextItem()
+				if item.Type != tokenEOF || !errors.Is(item.Err, test.err) {
+					t.Errorf("unexpected item: wanted an EOF item with error %v ; got %v", test.err, item)
+				}
+				if l.Len() > test.size {
+					t.Errorf("unexpected buffer size: wanted at most %d ; got %d", test.size, l.Len())
+				}
+			})
+		}
+	})
+}
+
+// panicState describes a StateFn that indexes the input out of its bounds
+func panicState[C uint, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	l.Next()
+	l.Emit((C)(tokenIdent))
+	_ = l.Extract(l.Pos(), l.Pos()-1)
+	return initState[C, T]
+}
+
+func TestRecover(t *testing.T) {
+	l := lex.New(panicState[uint, rune], testInput1)
+	l.Recover(true)
+
+	items := lex.Collect[uint, rune](l)
+	if len(items) != 2 {
+		t.Errorf("token slice length mismatch error: wanted %d ; got %d", 2, len(items))
+		return
+	}
+	if items[0].Type != tokenIdent {
+		t.Errorf("unexpected token type: wanted %d ; got %d", tokenIdent, items[0].Type)
+	}
+
+	var panicErr *lex.PanicError
+	if !errors.As(items[1].Err, &panicErr) {
+		t.Errorf("expected a panic error ; got %v", items[1].Err)
+		return
+	}
+	if len(panicErr.Stack) == 0 {
+		t.Errorf("expected panic error to hold a stack trace")
+	}
+	var stateErr *lex.StateError
+	if !errors.As(items[1].Err, &stateErr) || stateErr.Pos != 1 || !strings.Contains(stateErr.State, "panicState") {
+		t.Errorf("unexpected state error: %v", items[1].Err)
+	}
+}
+
+func TestAcceptSequences(t *testing.T) {
+	// input: `lexing data.`
+	for _, test := range []struct {
+		name string
+		new  func() lex.Lexer[uint, rune]
+	}{
+		{"Lex", func() lex.Lexer[uint, rune] {
+			return lex.New(initState[uint, rune], testInput1)
+		}},
+		{"LexBuffer", func() lex.Lexer[uint, rune] {
+			return lex.NewBuffer(initState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(testInput1)))
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			isSpace := func(item rune) bool { return item == ' ' }
+			isLetter := func(item rune) bool { return item >= 'a' && item <= 'z' }
+
+			l := test.new()
+			if lex.AcceptSeq(l, []rune("lexer")) || l.Pos() != 0 {
+				t.Errorf("expected sequence not to be accepted ; pos: %d", l.Pos())
+			}
+			if !lex.AcceptSeq(l, []rune("lex")) || l.Pos() != 3 {
+				t.Errorf("expected sequence to be accepted ; pos: %d", l.Pos())
+			}
+
+			l = test.new()
+			if idx := lex.AcceptAny(l, [][]rune{[]rune("le"), []rune("lexing"), []rune("lexer"), []rune("lex")}); idx != 1 || l.Pos() != 6 {
+				t.Errorf("unexpected longest match: wanted %d ; got %d ; pos: %d", 1, idx, l.Pos())
+			}
+			if idx := lex.AcceptAny(l, [][]rune{[]rune("x")}); idx != -1 || l.Pos() != 6 {
+				t.Errorf("unexpected match: wanted %d ; got %d ; pos: %d", -1, idx, l.Pos())
+			}
+
+			l = test.new()
+			if !l.(lex.Acceptor[rune]).AcceptUntil(isSpace) || l.Pos() != 6 {
+				t.Errorf("expected units to be accepted until a space ; pos: %d", l.Pos())
+			}
+			if l.(lex.Acceptor[rune]).AcceptUntil(func(item rune) bool { return item == '!' }) || l.Pos() != 6 {
+				t.Errorf("expected units not to be accepted without a terminator ; pos: %d", l.Pos())
+			}
+			if !lex.AcceptUntilSeq(l, []rune("a.")) || l.Pos() != 10 {
+				t.Errorf("expected units to be accepted until the sequence ; pos: %d", l.Pos())
+			}
+			if lex.AcceptUntilSeq(l, []rune("..")) || l.Pos() != 10 {
+				t.Errorf("expected units not to be accepted without the sequence ; pos: %d", l.Pos())
+			}
+
+			l = test.new()
+			if l.(lex.Acceptor[rune]).AcceptN(7, isLetter) || l.Pos() != 0 {
+				t.Errorf("expected units not to be accepted ; pos: %d", l.Pos())
+			}
+			if !l.(lex.Acceptor[rune]).AcceptN(4, isLetter) || l.Pos() != 4 {
+				t.Errorf("expected units to be accepted ; pos: %d", l.Pos())
+			}
+			lex.AcceptUntilSeq(l, []rune("."))
+			if l.(lex.Acceptor[rune]).AcceptN(2, func(rune) bool { return true }) || l.Pos() != 11 {
+				t.Errorf("expected units not to be accepted past the end of the input ; pos: %d", l.Pos())
+			}
+		})
+	}
+}
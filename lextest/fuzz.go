@@ -0,0 +1,135 @@
+package lextest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zalgonoise/gbuf"
+	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+	"github.com/zalgonoise/lex/class"
+)
+
+const (
+	// maxItemsPerUnit bounds the amount of items a lexer may emit for each unit in the input,
+	// before it is considered not to terminate
+	maxItemsPerUnit = 8
+	// maxStepsPerUnit bounds the amount of StateFn transitions a lexer may run for each unit in
+	// the input, before it is considered not to terminate
+	maxStepsPerUnit = 64
+)
+
+// Char is a constraint for text units, as bytes or runes
+type Char = class.Char
+
+// Fuzz registers a fuzz target in `f` that converts the fuzzed data into text units of type T
+// and verifies the lexer invariants for the StateFn `initFn`, as described in `Check()`
+//
+// The seed corpus should be added with `f.Add([]byte(...))` before calling Fuzz
+func Fuzz[C comparable, T Char](f *testing.F, initFn lex.StateFn[C, T]) {
+	f.Helper()
+	f.Fuzz(func(t *testing.T, data []byte) {
+		Check(t, initFn, units[T](data))
+	})
+}
+
+// units converts the input data into a slice of T; as bytes if T is byte-sized, or as runes
+// otherwise
+func units[T Char](data []byte) []T {
+	var wide rune = 0x100
+	if (T)(wide) == 0 {
+		out := make([]T, len(data))
+		for idx, b := range data {
+			out[idx] = (T)(b)
+		}
+		return out
+	}
+
+	runes := []rune(string(data))
+	out := make([]T, len(runes))
+	for idx, r := range runes {
+		out[idx] = (T)(r)
+	}
+	return out
+}
+
+// Check lexes the input `input` with the StateFn `initFn`, with both a Lex and a LexBuffer,
+// reporting a test error if any of the following invariants is broken:
+//   - the lexer terminates (returns an EOF item) within a bounded amount of items and StateFn
+//     transitions, without stalling
+//   - an empty input lexes to a single EOF item
+//   - the items' positions never decrease
+//   - the items' values match the input on their position, without overlapping; such that the
+//     values concatenated with the ignored spans between them reproduce the input
+//   - both Lex and LexBuffer emit identical item streams
+//
+// The bounds are enforced with the lexers' Limits, which are verified between StateFns; so a
+// single StateFn that never returns will still hang the test
+func Check[C comparable, T any](t testing.TB, initFn lex.StateFn[C, T], input []T) {
+	t.Helper()
+	limits := lex.WithLimits(lex.Limits{MaxSteps: (len(input) + 1) * maxStepsPerUnit})
+
+	l, err := lex.NewLex(initFn, input, limits)
+	if err != nil {
+		t.Fatalf("failed to create lexer: %v", err)
+	}
+	items, err := lexBounded[C, T](l, len(input))
+	if err != nil {
+		t.Fatalf("lexer did not terminate for input %v: %v", input, err)
+	}
+	if len(input) == 0 && (len(items) != 1 || items[0].Pos != 0 || len(items[0].Value) != 0) {
+		t.Fatalf("empty input did not lex to a single EOF item:\n%s", Diff(nil, items))
+	}
+
+	var end int
+	for idx, item := range items {
+		if item.Pos < end {
+			t.Fatalf("item #%d on position %d overlaps or precedes the previous item, ending on position %d:\n%s",
+				idx, item.Pos, end, Diff(nil, items),
+			)
+		}
+		if item.Pos+len(item.Value) > len(input) || !equalItem(item, lex.Item[C, T]{
+			Pos:   item.Pos,
+			Type:  item.Type,
+			Value: input[item.Pos : item.Pos+len(item.Value)],
+			Err:   item.Err,
+			Data:  item.Data,
+		}) {
+			t.Fatalf("item #%d does not match the input on position %d:\n%s", idx, item.Pos, Diff(nil, items))
+		}
+		end = item.Pos + len(item.Value)
+	}
+
+	b, err := lex.NewLexBuffer(initFn, (gio.Reader[T])(gbuf.NewReader(input)), limits)
+	if err != nil {
+		t.Fatalf("failed to create buffered lexer: %v", err)
+	}
+	buffered, err := lexBounded[C, T](b, len(input))
+	if err != nil {
+		t.Fatalf("buffered lexer did not terminate for input %v: %v", input, err)
+	}
+	if !Equal(items, buffered) {
+		t.Fatalf("Lex and LexBuffer items differ (-Lex +LexBuffer):\n%s", Diff(items, buffered))
+	}
+}
+
+// lexBounded collects the items from the Emitter `e` up to the EOF item, returning an error if
+// the lexer exceeds the items bound for an input of size `size`, or if it is stopped by its
+// Limits (with a *lex.StateError)
+func lexBounded[C comparable, T any](e lex.Emitter[C, T], size int) ([]lex.Item[C, T], error) {
+	var eof C
+	var items []lex.Item[C, T]
+	for i := 0; i < (size+1)*maxItemsPerUnit; i++ {
+		item := e.NextItem()
+		var stateErr *lex.StateError
+		if errors.As(item.Err, &stateErr) {
+			return items, stateErr
+		}
+		items = append(items, item)
+		if item.Type == eof {
+			return items, nil
+		}
+	}
+	return items, fmt.Errorf("exceeded %d items", (size+1)*maxItemsPerUnit)
+}
@@ -0,0 +1,215 @@
+// Package lextest provides helpers for table-driven testing of lex StateFns, comparing the
+// items emitted by a lexer against expected items or golden files
+package lextest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zalgonoise/lex"
+)
+
+const goldenExt = ".golden"
+
+// update is namespaced so that it does not clash with the `-update` flags commonly defined by
+// the test packages importing lextest
+var update = flag.Bool("lextest.update", false, "update the lextest golden files in testdata")
+
+// Case describes a single lexer test case, for a certain input
+//
+// The emitted items are compared against the `Items` slice, or against the golden
+// file `testdata/<Golden>.golden` if `Golden` is set
+type Case[C comparable, T any] struct {
+	Name   string
+	Input  []T
+	Items  []lex.Item[C, T]
+	Golden string
+}
+
+// Run executes each Case in `cases` as a subtest, lexing its input with the StateFn `initFn`
+// and comparing the emitted items against the expected ones
+func Run[C comparable, T any](t *testing.T, initFn lex.StateFn[C, T], cases ...Case[C, T]) {
+	t.Helper()
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Helper()
+			got := Lex(initFn, c.Input)
+			if c.Golden != "" {
+				Golden(t, c.Golden, got)
+				return
+			}
+			Compare(t, c.Items, got)
+		})
+	}
+}
+
+// Lex runs a lexer with the StateFn `initFn` over the input `input`, returning all emitted
+// items up to (and including) the EOF item
+func Lex[C comparable, T any](initFn lex.StateFn[C, T], input []T) []lex.Item[C, T] {
+	return lex.Collect[C, T](lex.New(initFn, input))
+}
+
+// Compare verifies that the items `got` match the expected items `wants`, reporting a
+// token-by-token diff as a test error if they don't. It returns true if the items match
+func Compare[C comparable, T any](t testing.TB, wants, got []lex.Item[C, T]) bool {
+	t.Helper()
+	if Equal(wants, got) {
+		return true
+	}
+	t.Errorf("unexpected items (-wanted +got):\n%s", Diff(wants, got))
+	return false
+}
+
+// Equal returns true if both item slices `a` and `b` hold the same items, with the same
+// positions, token types, values and data
+func Equal[C comparable, T any](a, b []lex.Item[C, T]) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if !equalItem(a[idx], b[idx]) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalItem[C comparable, T any](a, b lex.Item[C, T]) bool {
+	if a.Pos != b.Pos || a.Type != b.Type || len(a.Value) != len(b.Value) {
+		return false
+	}
+	if (a.Err == nil) != (b.Err == nil) || (a.Err != nil && a.Err.Error() != b.Err.Error()) {
+		return false
+	}
+	if !reflect.DeepEqual(a.Data, b.Data) {
+		return false
+	}
+	for idx := range a.Value {
+		if !reflect.DeepEqual(a.Value[idx], b.Value[idx]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff returns a token-by-token diff between the items `wants` and `got`, one item per line
+//
+// Matching items are prefixed with two spaces, while mismatching ones are prefixed with
+// `-` (wanted) and `+` (got)
+func Diff[C comparable, T any](wants, got []lex.Item[C, T]) string {
+	var sb = new(strings.Builder)
+	n := len(wants)
+	if len(got) > n {
+		n = len(got)
+	}
+	for idx := 0; idx < n; idx++ {
+		switch {
+		case idx >= len(wants):
+			writeLine(sb, "+ ", idx, got[idx])
+		case idx >= len(got):
+			writeLine(sb, "- ", idx, wants[idx])
+		case equalItem(wants[idx], got[idx]):
+			writeLine(sb, "  ", idx, wants[idx])
+		default:
+			writeLine(sb, "- ", idx, wants[idx])
+			writeLine(sb, "+ ", idx, got[idx])
+		}
+	}
+	return sb.String()
+}
+
+func writeLine[C comparable, T any](sb *strings.Builder, prefix string, idx int, item lex.Item[C, T]) {
+	sb.WriteString(prefix)
+	sb.WriteByte('#')
+	sb.WriteString(strconv.Itoa(idx))
+	sb.WriteByte('\t')
+	sb.WriteString(FormatItem(item))
+	sb.WriteByte('\n')
+}
+
+// Format returns the text representation of the items `items`, one item per line, as
+// written to golden files
+func Format[C comparable, T any](items []lex.Item[C, T]) string {
+	var sb = new(strings.Builder)
+	for _, item := range items {
+		sb.WriteString(FormatItem(item))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// FormatItem returns the text representation of the item `item`, as a tab-separated set of
+// its position, token and value (and data and error, if set)
+//
+// Values are formatted with the item's `Quote()` method
+func FormatItem[C comparable, T any](item lex.Item[C, T]) string {
+	line := fmt.Sprintf("%d\t%v\t%s", item.Pos, item.Type, item.Quote())
+	if item.Data != nil {
+		line = fmt.Sprintf("%s\tdata: %v", line, item.Data)
+	}
+	if item.Err != nil {
+		line = fmt.Sprintf("%s\t%v", line, item.Err)
+	}
+	return line
+}
+
+// Golden compares the text representation of the items `got` against the golden file
+// `testdata/<name>.golden`, reporting a line-by-line diff as a test error if they don't match
+//
+// If the test is executed with the `-lextest.update` flag, the golden file is (re)written instead
+func Golden[C comparable, T any](t testing.TB, name string, got []lex.Item[C, T]) {
+	t.Helper()
+	path := filepath.Join("testdata", name+goldenExt)
+	output := Format(got)
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden file directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(output), 0o644); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	wants, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -lextest.update to create it): %v", err)
+	}
+	if string(wants) == output {
+		return
+	}
+	t.Errorf("unexpected items against %s (-wanted +got):\n%s", path, diffLines(
+		strings.Split(strings.TrimSuffix(string(wants), "\n"), "\n"),
+		strings.Split(strings.TrimSuffix(output, "\n"), "\n"),
+	))
+}
+
+func diffLines(wants, got []string) string {
+	var sb = new(strings.Builder)
+	n := len(wants)
+	if len(got) > n {
+		n = len(got)
+	}
+	for idx := 0; idx < n; idx++ {
+		switch {
+		case idx >= len(wants):
+			fmt.Fprintf(sb, "+ #%d\t%s\n", idx, got[idx])
+		case idx >= len(got):
+			fmt.Fprintf(sb, "- #%d\t%s\n", idx, wants[idx])
+		case wants[idx] == got[idx]:
+			fmt.Fprintf(sb, "  #%d\t%s\n", idx, wants[idx])
+		default:
+			fmt.Fprintf(sb, "- #%d\t%s\n", idx, wants[idx])
+			fmt.Fprintf(sb, "+ #%d\t%s\n", idx, got[idx])
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,108 @@
+package lextest_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zalgonoise/lex"
+	"github.com/zalgonoise/lex/lextest"
+)
+
+type wordToken uint8
+
+const (
+	tokenEOF wordToken = iota
+	tokenWord
+	tokenSpace
+)
+
+func (t wordToken) String() string {
+	switch t {
+	case tokenWord:
+		return "word"
+	case tokenSpace:
+		return "space"
+	default:
+		return "EOF"
+	}
+}
+
+// initWords describes a StateFn that splits the input into words and spaces
+func initWords[C wordToken, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	switch l.Next() {
+	case 0:
+		l.Emit((C)(tokenEOF))
+		return nil
+	case ' ':
+		for l.Check(func(item T) bool {
+			return item == ' '
+		}) {
+			l.Next()
+		}
+		l.Emit((C)(tokenSpace))
+	default:
+		for l.Check(func(item T) bool {
+			return item != ' ' && item != 0
+		}) {
+			l.Next()
+		}
+		l.Emit((C)(tokenWord))
+	}
+	return initWords[C, T]
+}
+
+func TestRun(t *testing.T) {
+	lextest.Run(t, initWords[wordToken, rune],
+		lextest.Case[wordToken, rune]{
+			Name:  "Single",
+			Input: []rune("lex"),
+			Items: []lex.Item[wordToken, rune]{
+				lex.NewItem(0, tokenWord, []rune("lex")...),
+				lex.NewItem[wordToken, rune](3, tokenEOF),
+			},
+		},
+		lextest.Case[wordToken, rune]{
+			Name:  "Empty",
+			Input: []rune{},
+			Items: []lex.Item[wordToken, rune]{
+				lex.NewItem[wordToken, rune](0, tokenEOF),
+			},
+		},
+		lextest.Case[wordToken, rune]{
+			Name:   "Golden",
+			Input:  []rune("lexing  some data"),
+			Golden: "words",
+		},
+	)
+}
+
+func TestDiff(t *testing.T) {
+	wants := []lex.Item[wordToken, rune]{
+		lex.NewItem(0, tokenWord, []rune("lex")...),
+		lex.NewItem[wordToken, rune](3, tokenEOF),
+	}
+	got := lextest.Lex(initWords[wordToken, rune], []rune("le x"))
+
+	if lextest.Equal(wants, got) {
+		t.Errorf("expected items not to match")
+	}
+
+	diff := lextest.Diff(wants, got)
+	for _, line := range []string{
+		"- #0\t0\tword\t\"lex\"",
+		"+ #0\t0\tword\t\"le\"",
+		"+ #2\t3\tword\t\"x\"",
+	} {
+		if !strings.Contains(diff, line) {
+			t.Errorf("expected diff to contain %q ; got:\n%s", line, diff)
+		}
+	}
+}
+
+func FuzzWords(f *testing.F) {
+	f.Add([]byte("lexing  some data"))
+	f.Add([]byte(" lex "))
+	f.Add([]byte(""))
+
+	lextest.Fuzz(f, initWords[wordToken, rune])
+}
@@ -0,0 +1,249 @@
+package lex
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+)
+
+// DefaultMaxStall is the default number of consecutive StateFn transitions without progress
+// that a lexer allows before stopping
+const DefaultMaxStall = 1024
+
+var (
+	// ErrNoProgress is a preset error for lexers whose StateFns keep transitioning without
+	// moving the cursor or emitting items
+	ErrNoProgress = errors.New("lexer made no progress")
+	// ErrMaxSteps is a preset error for lexers that exhaust their StateFn transitions budget
+	ErrMaxSteps = errors.New("lexer exceeded its step budget")
+	// ErrMaxWidth is a preset error for items spanning over more units than the lexer allows
+	ErrMaxWidth = errors.New("item exceeded the maximum width")
+	// ErrMaxBuffered is a preset error for buffered lexers holding more units in their buffer
+	// than they allow
+	ErrMaxBuffered = errors.New("lexer exceeded its buffer size")
+	// ErrMaxItems is a preset error for lexers emitting more items than they allow
+	ErrMaxItems = errors.New("lexer exceeded its item count")
+	// ErrMaxDepth is a preset error for lexers entering more nesting levels than they allow
+	ErrMaxDepth = errors.New("lexer exceeded its nesting depth")
+)
+
+// Limits describes the bounds for a lexer's runtime, that stop it when exceeded
+//
+// Each limit stops the lexer with a diagnostic EOF item carrying a *StateError, which wraps the
+// limit's own preset error (such as ErrMaxWidth), so it can be told apart with `errors.Is()`.
+// For untrusted input, setting MaxWidth and MaxBuffered bounds the memory held by a LexBuffer,
+// even if its StateFns never emit an item (like on an unterminated string literal)
+type Limits struct {
+	// MaxStall is the number of consecutive StateFn transitions without progress (not moving
+	// the cursor nor the starting point past the furthest point they reached, and not emitting
+	// any items) that the lexer allows. StateFns that move the cursor back and forth over the
+	// same units, across any number of transitions, are not making progress
+	//
+	// Zero uses DefaultMaxStall, and a negative value disables the check
+	MaxStall int
+
+	// MaxSteps is the total number of StateFn transitions that the lexer allows.
+	//
+	// Zero or a negative value disables the check
+	MaxSteps int
+
+	// MaxWidth is the number of units that a single item can span over. Items exceeding it are
+	// not emitted; and a LexBuffer does not read more than MaxWidth units past its starting point.
+	//
+	// Zero or a negative value disables the check
+	MaxWidth int
+
+	// MaxBuffered is the number of units that a LexBuffer can hold in its buffer, above which it
+	// stops reading from its input. It is ignored by a Lex, whose input is already in memory.
+	//
+	// Zero or a negative value disables the check
+	MaxBuffered int
+
+	// MaxItems is the total number of items (on any channel, including the EOF items emitted by
+	// the StateFns) that the lexer can emit.
+	//
+	// Zero or a negative value disables the check
+	MaxItems int
+
+	// MaxDepth is the number of nesting levels that the lexer's StateFns can enter, with the
+	// Nester's `Enter()` method.
+	//
+	// Zero or a negative value disables the check
+	MaxDepth int
+}
+
+// StateError describes a diagnostic error raised when running a lexer's StateFn, such as
+// when exceeding its limits
+//
+// It wraps the underlying error, so it can be checked with `errors.Is()`
+type StateError struct {
+	Err   error
+	State string
+	Pos   int
+}
+
+// Error implements the error interface
+func (e *StateError) Error() string {
+	return fmt.Sprintf("%s on position %d, in StateFn %s", e.Err.Error(), e.Pos, e.State)
+}
+
+// Unwrap returns the underlying error
+func (e *StateError) Unwrap() error {
+	return e.Err
+}
+
+// guard keeps track of a lexer's StateFn transitions, items and nesting depth, verifying them
+// against its Limits
+//
+// Limits exceeded within a StateFn (such as MaxWidth) are kept in `err`, and reported once the
+// StateFn returns
+type guard struct {
+	limits Limits
+	steps  int
+	stalls int
+	items  int
+	depth  int
+	err    error
+
+	// the furthest starting point and cursor position reached, and the emitted items count, as
+	// of the last StateFn transition
+	start   int
+	pos     int
+	emitted int
+}
+
+// before verifies the limits ahead of a StateFn transition, returning an error if the step
+// budget is exhausted
+func (g *guard) before() error {
+	if g.limits.MaxSteps > 0 && g.steps >= g.limits.MaxSteps {
+		return ErrMaxSteps
+	}
+	g.steps++
+	return nil
+}
+
+// reset clears the counted StateFn transitions, items and nesting depth, keeping the Limits
+func (g *guard) reset() {
+	g.steps = 0
+	g.stalls = 0
+	g.items = 0
+	g.depth = 0
+	g.err = nil
+	g.start = 0
+	g.pos = 0
+	g.emitted = 0
+}
+
+// rewind moves the furthest starting point and cursor position back to the position `pos`, as
+// the lexer is rewound to lex the input from there again
+func (g *guard) rewind(pos int) {
+	g.start = pos
+	g.pos = pos
+	g.stalls = 0
+}
+
+// exceed keeps the error `err` for a limit exceeded within a StateFn, returning it
+func (g *guard) exceed(err error) error {
+	if g.err == nil {
+		g.err = err
+	}
+	return g.err
+}
+
+// item verifies the limits for an item spanning over `width` units, ahead of emitting it,
+// returning an error if it exceeds MaxWidth or MaxItems (or if a limit was already exceeded)
+func (g *guard) item(width int) error {
+	switch {
+	case g.err != nil:
+		return g.err
+	case g.limits.MaxWidth > 0 && width > g.limits.MaxWidth:
+		return g.exceed(ErrMaxWidth)
+	case g.limits.MaxItems > 0 && g.items >= g.limits.MaxItems:
+		return g.exceed(ErrMaxItems)
+	}
+	g.items++
+	return nil
+}
+
+// read verifies the limits for a LexBuffer ahead of reading a unit into its buffer, holding
+// `buffered` units with `width` units past its starting point; returning an error if it would
+// exceed MaxWidth or MaxBuffered (or if a limit was already exceeded)
+func (g *guard) read(buffered, width int) error {
+	switch {
+	case g.err != nil:
+		return g.err
+	case g.limits.MaxWidth > 0 && width > g.limits.MaxWidth:
+		// allows reading one unit past MaxWidth, to find the end of an item
+		return g.exceed(ErrMaxWidth)
+	case g.limits.MaxBuffered > 0 && buffered >= g.limits.MaxBuffered:
+		return g.exceed(ErrMaxBuffered)
+	}
+	return nil
+}
+
+// enter increments the nesting depth, returning an error if it exceeds MaxDepth
+func (g *guard) enter() error {
+	if g.err != nil {
+		return g.err
+	}
+	if g.limits.MaxDepth > 0 && g.depth >= g.limits.MaxDepth {
+		return g.exceed(ErrMaxDepth)
+	}
+	g.depth++
+	return nil
+}
+
+// leave decrements the nesting depth
+func (g *guard) leave() {
+	if g.depth > 0 {
+		g.depth--
+	}
+}
+
+// after verifies the limits following a StateFn transition, with the lexer's (absolute) starting
+// point `start`, cursor position `pos` and emitted items count `emitted`; returning an error if a
+// limit was exceeded within the StateFn, or if the lexer has stalled for too long
+//
+// The lexer makes progress if it emits an item, or if its starting point or its cursor move past
+// the furthest point they reached so far; so StateFns that move the cursor back and forth over
+// the same units (even across several transitions) are detected as stalled
+func (g *guard) after(start, pos, emitted int) error {
+	if g.err != nil {
+		return g.err
+	}
+	progress := emitted != g.emitted || start > g.start || pos > g.pos
+	g.emitted = emitted
+	if start > g.start {
+		g.start = start
+	}
+	if pos > g.pos {
+		g.pos = pos
+	}
+
+	if progress || g.limits.MaxStall < 0 {
+		g.stalls = 0
+		return nil
+	}
+	g.stalls++
+
+	maxStall := g.limits.MaxStall
+	if maxStall == 0 {
+		maxStall = DefaultMaxStall
+	}
+	if g.stalls >= maxStall {
+		return ErrNoProgress
+	}
+	return nil
+}
+
+// stateName returns the (function) name of the StateFn `fn`
+func stateName[C comparable, T any](fn StateFn[C, T]) string {
+	if fn == nil {
+		return "<nil>"
+	}
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return "<unknown>"
+}
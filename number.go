@@ -0,0 +1,266 @@
+package lex
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/zalgonoise/lex/class"
+)
+
+var (
+	// ErrInvalidNumber is a preset error for malformed numeric literals, such as a base prefix
+	// without digits, a misplaced digit separator or a digit out of the literal's base
+	ErrInvalidNumber = errors.New("invalid numeric literal")
+	// ErrNumberRange is a preset error for numeric literals that overflow their decoded type
+	ErrNumberRange = errors.New("numeric literal out of range")
+)
+
+// NumberSyntax defines the syntax rules for numeric literals scanned by a Number, as a set of
+// flags
+type NumberSyntax uint8
+
+const (
+	// NumberHex accepts hexadecimal integers, prefixed with `0x` or `0X`
+	NumberHex NumberSyntax = 1 << iota
+	// NumberOctal accepts octal integers, prefixed with `0o` or `0O`
+	NumberOctal
+	// NumberBinary accepts binary integers, prefixed with `0b` or `0B`
+	NumberBinary
+	// NumberFloat accepts decimal floating-point numbers, with a fractional part (a period
+	// followed by at least one digit) and / or an exponent (like `1.5e10` or `2E-3`)
+	NumberFloat
+	// NumberLeadingDot accepts floating-point numbers starting with a period (like `.5`), when
+	// combined with NumberFloat
+	NumberLeadingDot
+	// NumberSeparator accepts `_` as a digit separator, between digits or after a base prefix
+	// (like `1_000` or `0x_FF`)
+	NumberSeparator
+
+	// NumberDefault accepts all numeric literal forms, like Go's integer and decimal
+	// floating-point literals
+	NumberDefault = NumberHex | NumberOctal | NumberBinary | NumberFloat | NumberLeadingDot | NumberSeparator
+)
+
+// NumberTokens describes the token types emitted by a Number
+type NumberTokens[C comparable] struct {
+	// Int is the token type for integer literals, whose Data is the decoded uint64 value
+	Int C
+	// Float is the token type for floating-point literals, whose Data is the decoded float64 value
+	Float C
+	// Error is the token type for the error items, on malformed or out of range literals
+	Error C
+}
+
+// Number scans numeric literals in byte or rune lexers, following a configurable NumberSyntax;
+// as a fragment to be called from a StateFn, with its `Scan()` method
+//
+// Integer literals are decoded as uint64 values (as literals carry no sign, which is usually
+// lexed as an operator) and floating-point literals as float64 values, set as the emitted item's
+// Data (if the lexer is a ValueEmitter). Leading zeros in decimal literals are not read as an
+// octal prefix
+type Number[C comparable, T class.Char] struct {
+	tokens NumberTokens[C]
+	syntax NumberSyntax
+}
+
+// NewNumber creates a Number emitting the token types in `tokens`, for the numeric literals in
+// the NumberSyntax `syntax`
+func NewNumber[C comparable, T class.Char](tokens NumberTokens[C], syntax NumberSyntax) *Number[C, T] {
+	return &Number[C, T]{
+		tokens: tokens,
+		syntax: syntax,
+	}
+}
+
+// Scan lexes the numeric literal on the lexer's cursor, emitting it as an Int or Float item
+// holding its decoded value, and returns true. The lexer's starting point must be on its cursor
+// (with no units pending to be emitted or ignored)
+//
+// Malformed literals are consumed up to the end of their alphanumeric run (so `0b12` or `1e`
+// are emitted whole) as an Error item wrapping ErrInvalidNumber; while literals that overflow
+// their type are emitted as an Error item wrapping ErrNumberRange
+//
+// If the cursor is not on a numeric literal, nothing is consumed and false is returned
+func (n *Number[C, T]) Scan(l Lexer[C, T]) bool {
+	cur := l.Cur()
+	leadingDot := cur == '.' && n.syntax&(NumberFloat|NumberLeadingDot) == NumberFloat|NumberLeadingDot
+	if !isDigit(cur, 10) && !(leadingDot && isDigit(l.Peek(), 10)) {
+		return false
+	}
+
+	base, valid := 10, true
+	if cur == '0' {
+		switch l.Peek() {
+		case 'x', 'X':
+			base = n.prefix(NumberHex, 16)
+		case 'o', 'O':
+			base = n.prefix(NumberOctal, 8)
+		case 'b', 'B':
+			base = n.prefix(NumberBinary, 2)
+		}
+	}
+
+	var float bool
+	if base != 10 {
+		l.Next()
+		l.Next()
+		digits, ok := n.digits(l, base, true)
+		valid = ok && digits > 0
+	} else {
+		_, valid = n.digits(l, base, false)
+		if n.syntax&NumberFloat != 0 {
+			if l.Cur() == '.' && isDigit(l.Peek(), 10) {
+				float = true
+				l.Next()
+				if _, ok := n.digits(l, base, false); !ok {
+					valid = false
+				}
+			}
+			if cur := l.Cur(); cur == 'e' || cur == 'E' {
+				float = true
+				l.Next()
+				if cur := l.Cur(); cur == '+' || cur == '-' {
+					l.Next()
+				}
+				if digits, ok := n.digits(l, base, false); !ok || digits == 0 {
+					valid = false
+				}
+			}
+		}
+	}
+
+	// a literal followed by alphanumeric units (like `0b12` or `12ab`) is malformed
+	if cur := l.Cur(); cur == '_' || isDigit(cur, 36) {
+		valid = false
+		l.AcceptRun(func(item T) bool { return item == '_' || isDigit(item, 36) })
+	}
+	if !valid {
+		emitError(l, n.tokens.Error, fmt.Errorf("%w: %q", ErrInvalidNumber, string(runes(l.Extract(l.Start(), l.Pos())))))
+		return true
+	}
+
+	units := l.Extract(l.Start(), l.Pos())
+	if float {
+		value, err := strconv.ParseFloat(string(digitRunes(units)), 64)
+		if err != nil {
+			emitError(l, n.tokens.Error, fmt.Errorf("%w: %q", ErrNumberRange, string(runes(units))))
+			return true
+		}
+		emitData(l, n.tokens.Float, value)
+		return true
+	}
+
+	if base != 10 {
+		units = units[2:]
+	}
+	value, ok := decode(units, base)
+	if !ok {
+		emitError(l, n.tokens.Error, fmt.Errorf("%w: %q", ErrNumberRange, string(runes(l.Extract(l.Start(), l.Pos())))))
+		return true
+	}
+	emitData(l, n.tokens.Int, value)
+	return true
+}
+
+// prefix returns the base `base` if the NumberSyntax accepts its prefix `flag`, or 10 otherwise
+func (n *Number[C, T]) prefix(flag NumberSyntax, base int) int {
+	if n.syntax&flag == 0 {
+		return 10
+	}
+	return base
+}
+
+// digits consumes a run of digits in the base `base`, with any separators allowed by the
+// NumberSyntax; returning the number of digits and false if a separator is misplaced
+//
+// A leading separator is only allowed if `prefixed` is set
+func (n *Number[C, T]) digits(l Lexer[C, T], base int, prefixed bool) (count int, ok bool) {
+	allowed := n.syntax&NumberSeparator != 0
+	// sep is set if a separator would be misplaced on the cursor: on the start of the run (unless
+	// prefixed), and after another separator
+	sep, ok := !prefixed, true
+	for {
+		switch cur := l.Cur(); {
+		case isDigit(cur, base):
+			l.Next()
+			count++
+			sep = false
+		case allowed && cur == '_':
+			if sep {
+				ok = false
+			}
+			l.Next()
+			sep = true
+		default:
+			if sep && count > 0 {
+				// trailing separator
+				ok = false
+			}
+			return count, ok
+		}
+	}
+}
+
+// isDigit returns true if the unit `unit` is a digit in the base `base` (up to 36)
+func isDigit[T class.Char](unit T, base int) bool {
+	var value int
+	switch {
+	case unit >= '0' && unit <= '9':
+		value = int(unit - '0')
+	case unit >= 'a' && unit <= 'z':
+		value = int(unit-'a') + 10
+	case unit >= 'A' && unit <= 'Z':
+		value = int(unit-'A') + 10
+	default:
+		return false
+	}
+	return value < base
+}
+
+// decode returns the uint64 value of the digits `units` in the base `base`, ignoring any
+// separators; or false if it overflows
+func decode[T class.Char](units []T, base int) (uint64, bool) {
+	var value uint64
+	for _, unit := range units {
+		if unit == '_' {
+			continue
+		}
+		var digit uint64
+		switch {
+		case unit >= '0' && unit <= '9':
+			digit = uint64(unit - '0')
+		case unit >= 'a' && unit <= 'z':
+			digit = uint64(unit-'a') + 10
+		default:
+			digit = uint64(unit-'A') + 10
+		}
+		if value > (^uint64(0)-digit)/uint64(base) {
+			return 0, false
+		}
+		value = value*uint64(base) + digit
+	}
+	return value, true
+}
+
+// digitRunes converts the units `units` into runes, skipping any separators
+func digitRunes[T class.Char](units []T) []rune {
+	buf := make([]rune, 0, len(units))
+	for _, unit := range units {
+		if unit != '_' {
+			buf = append(buf, (rune)(unit))
+		}
+	}
+	return buf
+}
+
+// runes converts the units `units` into runes, to be formatted or parsed as a string
+//
+// Byte units are converted one by one, which is accurate for the ASCII text of numeric literals
+func runes[T class.Char](units []T) []rune {
+	buf := make([]rune, len(units))
+	for idx := range units {
+		buf[idx] = (rune)(units[idx])
+	}
+	return buf
+}
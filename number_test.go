@@ -0,0 +1,114 @@
+package lex_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zalgonoise/gbuf"
+	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+)
+
+var numberTokens = lex.NumberTokens[uint]{
+	Int:   tokenInt,
+	Float: tokenFloat,
+	Error: tokenError,
+}
+
+func TestNumber(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		syntax lex.NumberSyntax
+		input  string
+		token  uint
+		value  string
+		data   any
+		err    error
+	}{
+		{"Int", lex.NumberDefault, "42", tokenInt, "42", uint64(42), nil},
+		{"Zero", lex.NumberDefault, "0", tokenInt, "0", uint64(0), nil},
+		{"LeadingZeros", lex.NumberDefault, "0755", tokenInt, "0755", uint64(755), nil},
+		{"Hex", lex.NumberDefault, "0xFf", tokenInt, "0xFf", uint64(255), nil},
+		{"Octal", lex.NumberDefault, "0o17", tokenInt, "0o17", uint64(15), nil},
+		{"Binary", lex.NumberDefault, "0B101", tokenInt, "0B101", uint64(5), nil},
+		{"Separators", lex.NumberDefault, "1_000_000", tokenInt, "1_000_000", uint64(1000000), nil},
+		{"PrefixSeparator", lex.NumberDefault, "0x_FF", tokenInt, "0x_FF", uint64(255), nil},
+		{"MaxUint64", lex.NumberDefault, "18446744073709551615", tokenInt, "18446744073709551615", uint64(1<<64 - 1), nil},
+		{"Float", lex.NumberDefault, "1.5", tokenFloat, "1.5", 1.5, nil},
+		{"Exponent", lex.NumberDefault, "1.5e10", tokenFloat, "1.5e10", 1.5e10, nil},
+		{"SignedExponent", lex.NumberDefault, "2E-3", tokenFloat, "2E-3", 2e-3, nil},
+		{"LeadingDot", lex.NumberDefault, ".5", tokenFloat, ".5", .5, nil},
+		{"FloatSeparators", lex.NumberDefault, "1_0.2_5", tokenFloat, "1_0.2_5", 10.25, nil},
+		{"TrailingDot", lex.NumberDefault, "1.", tokenInt, "1", uint64(1), nil},
+
+		{"NoHex", lex.NumberFloat, "0x1", tokenError, "0x1", nil, lex.ErrInvalidNumber},
+		{"NoFloat", lex.NumberHex, "1.5", tokenInt, "1", uint64(1), nil},
+		{"NoExponent", lex.NumberHex, "1e5", tokenError, "1e5", nil, lex.ErrInvalidNumber},
+		{"NoSeparator", lex.NumberFloat, "1_0", tokenError, "1_0", nil, lex.ErrInvalidNumber},
+		{"NoLeadingDot", lex.NumberFloat, ".5", tokenPeriod, ".", nil, nil},
+
+		{"EmptyPrefix", lex.NumberDefault, "0x", tokenError, "0x", nil, lex.ErrInvalidNumber},
+		{"BadDigit", lex.NumberDefault, "0b12", tokenError, "0b12", nil, lex.ErrInvalidNumber},
+		{"Suffix", lex.NumberDefault, "12ab", tokenError, "12ab", nil, lex.ErrInvalidNumber},
+		{"DoubleSeparator", lex.NumberDefault, "1__0", tokenError, "1__0", nil, lex.ErrInvalidNumber},
+		{"TrailingSeparator", lex.NumberDefault, "10_", tokenError, "10_", nil, lex.ErrInvalidNumber},
+		{"EmptyExponent", lex.NumberDefault, "1e+", tokenError, "1e+", nil, lex.ErrInvalidNumber},
+		{"IntOverflow", lex.NumberDefault, "18446744073709551616", tokenError, "18446744073709551616", nil, lex.ErrNumberRange},
+		{"FloatOverflow", lex.NumberDefault, "1e400", tokenError, "1e400", nil, lex.ErrNumberRange},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			verify := func(t *testing.T, item lex.Item[uint, rune]) {
+				if item.Pos != 0 || item.Type != test.token || string(item.Value) != test.value {
+					t.Errorf("unexpected item: wanted %d %q on position %d ; got %v", test.token, test.value, 0, item)
+				}
+				if item.Data != test.data {
+					t.Errorf("unexpected data: wanted %v ; got %v", test.data, item.Data)
+				}
+				if !errors.Is(item.Err, test.err) || (test.err == nil) != (item.Err == nil) {
+					t.Errorf("unexpected error: wanted %v ; got %v", test.err, item.Err)
+				}
+			}
+
+			input := []rune(test.input)
+			state := scanState(lex.NewNumber[uint, rune](numberTokens, test.syntax).Scan)
+
+			t.Run("Lex", func(t *testing.T) {
+				verify(t, lex.New(state, input).NextItem())
+			})
+			t.Run("LexBuffer", func(t *testing.T) {
+				verify(t, lex.NewBuffer(state, (gio.Reader[rune])(gbuf.NewReader(input))).NextItem())
+			})
+			t.Run("Bytes", func(t *testing.T) {
+				state := scanState(lex.NewNumber[uint, byte](numberTokens, test.syntax).Scan)
+				item := lex.New(state, []byte(test.input)).NextItem()
+				verify(t, lex.Item[uint, rune]{Pos: item.Pos, Type: item.Type, Value: []rune(string(item.Value)), Err: item.Err, Data: item.Data})
+			})
+		})
+	}
+}
+
+func TestNumberSequence(t *testing.T) {
+	input := []rune("3.14 0x1F 1e3.x")
+	items := lex.Collect[uint, rune](lex.New(scanState(lex.NewNumber[uint, rune](numberTokens, lex.NumberDefault).Scan), input))
+
+	wants := []struct {
+		token uint
+		value string
+	}{
+		{tokenFloat, "3.14"},
+		{tokenInt, "0x1F"},
+		{tokenFloat, "1e3"},
+		{tokenPeriod, "."},
+		{tokenIdent, "x"},
+		{tokenEOF, ""},
+	}
+	if len(items) != len(wants) {
+		t.Errorf("token slice length mismatch error: wanted %d ; got %d: %v", len(wants), len(items), items)
+		return
+	}
+	for idx, want := range wants {
+		if items[idx].Type != want.token || string(items[idx].Value) != want.value {
+			t.Errorf("unexpected item #%d: wanted %d %q ; got %v", idx, want.token, want.value, items[idx])
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package lex
+
+// Operator maps a sequence of units `Seq` (such as `>>=`) to its token type `Type`, as an entry
+// in an Operators table
+type Operator[C comparable, T comparable] struct {
+	Seq  []T
+	Type C
+}
+
+// Operators is a table of operators (or any other punctuation), stored as a trie; that scans the
+// longest operator matching the input, as a fragment to be called from a StateFn, with its
+// `Scan()` method
+//
+// Matching an operator reads each unit once while walking the trie, so it is faster than trying
+// each operator with the Accept methods (or with AcceptAny). The walk follows the longest prefix
+// of any operator in the input, and then backs up to the end of the longest complete operator
+// found on the way (or to where it started, if none was found); so it backs up over one unit less
+// than the longest operator, at most (like `..x` for a `...` operator, which reads two units and
+// backs up over both). A LexBuffer reads those units on demand. An Operators is read-only once
+// created, so it can be shared across lexers
+type Operators[C comparable, T comparable] struct {
+	nodes []opNode[C, T]
+}
+
+// opNode is a node in an Operators trie, holding the token type of the operator ending on it (if
+// any) and the edges to its child nodes
+type opNode[C comparable, T comparable] struct {
+	edges []opEdge[T]
+	token C
+	ok    bool
+}
+
+// opEdge links an opNode to its child node on index `next`, through the unit `unit`
+type opEdge[T comparable] struct {
+	unit T
+	next int
+}
+
+// NewOperators creates an Operators table from the operators `ops`, as a list of pairs since
+// slices cannot be used as map keys
+//
+// Empty sequences are ignored, and later entries replace the token types of earlier entries with
+// the same sequence
+func NewOperators[C comparable, T comparable](ops ...Operator[C, T]) *Operators[C, T] {
+	o := &Operators[C, T]{
+		nodes: make([]opNode[C, T], 1, len(ops)+1),
+	}
+	for _, op := range ops {
+		if len(op.Seq) == 0 {
+			continue
+		}
+		node := 0
+		for _, unit := range op.Seq {
+			next, ok := o.child(node, unit)
+			if !ok {
+				next = len(o.nodes)
+				o.nodes = append(o.nodes, opNode[C, T]{})
+				o.nodes[node].edges = append(o.nodes[node].edges, opEdge[T]{unit: unit, next: next})
+			}
+			node = next
+		}
+		o.nodes[node].token = op.Type
+		o.nodes[node].ok = true
+	}
+	return o
+}
+
+// child returns the index of the child node of node `node` through the unit `unit`, and true; or
+// false if there is no such node
+func (o *Operators[C, T]) child(node int, unit T) (int, bool) {
+	for _, edge := range o.nodes[node].edges {
+		if edge.unit == unit {
+			return edge.next, true
+		}
+	}
+	return 0, false
+}
+
+// Match consumes the longest operator matching the input from the lexer's cursor, returning its
+// token type and true
+//
+// If no operator matches the input, the cursor is not moved, and false is returned
+func (o *Operators[C, T]) Match(l Lexer[C, T]) (itemType C, ok bool) {
+	start := l.Pos()
+	end := start
+	for node := 0; ; {
+		next, found := o.child(node, l.Cur())
+		if !found {
+			break
+		}
+		pos := l.Pos()
+		if l.Next(); l.Pos() == pos {
+			// EOF
+			break
+		}
+		node = next
+		if o.nodes[node].ok {
+			itemType, ok, end = o.nodes[node].token, true, l.Pos()
+		}
+	}
+	rewind(l, end)
+	return itemType, ok
+}
+
+// Scan lexes the longest operator matching the input from the lexer's cursor, emitting it as an
+// item of its token type, and returns true. The lexer's starting point must be on its cursor
+// (with no units pending to be emitted or ignored)
+//
+// If no operator matches the input, nothing is consumed and false is returned
+func (o *Operators[C, T]) Scan(l Lexer[C, T]) bool {
+	itemType, ok := o.Match(l)
+	if !ok {
+		return false
+	}
+	l.Emit(itemType)
+	return true
+}
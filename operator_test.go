@@ -0,0 +1,136 @@
+package lex_test
+
+import (
+	"testing"
+
+	"github.com/zalgonoise/gbuf"
+	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+	"github.com/zalgonoise/lex/lextest"
+)
+
+var operators = lex.NewOperators(
+	lex.Operator[uint, rune]{Seq: []rune(">"), Type: tokenGT},
+	lex.Operator[uint, rune]{Seq: []rune(">>"), Type: tokenShr},
+	lex.Operator[uint, rune]{Seq: []rune(">>="), Type: tokenShrAssign},
+	lex.Operator[uint, rune]{Seq: []rune(">="), Type: tokenGE},
+	lex.Operator[uint, rune]{Seq: []rune("="), Type: tokenAssign},
+	lex.Operator[uint, rune]{Seq: []rune("=="), Type: tokenEQ},
+	lex.Operator[uint, rune]{Seq: []rune("!="), Type: tokenNE},
+	lex.Operator[uint, rune]{Seq: []rune("..."), Type: tokenEllipsis},
+)
+
+func TestOperators(t *testing.T) {
+	input := []rune("a>>=b>=c>>d!=e!f==g=..h...>>")
+	wants := []lex.Item[uint, rune]{
+		lex.NewItem(0, tokenIdent, 'a'),
+		lex.NewItem(1, tokenShrAssign, []rune(">>=")...),
+		lex.NewItem(4, tokenIdent, 'b'),
+		lex.NewItem(5, tokenGE, []rune(">=")...),
+		lex.NewItem(7, tokenIdent, 'c'),
+		lex.NewItem(8, tokenShr, []rune(">>")...),
+		lex.NewItem(10, tokenIdent, 'd'),
+		lex.NewItem(11, tokenNE, []rune("!=")...),
+		lex.NewItem(13, tokenIdent, 'e'),
+		lex.NewItem(14, tokenPeriod, '!'),
+		lex.NewItem(15, tokenIdent, 'f'),
+		lex.NewItem(16, tokenEQ, []rune("==")...),
+		lex.NewItem(18, tokenIdent, 'g'),
+		lex.NewItem(19, tokenAssign, '='),
+		lex.NewItem(20, tokenPeriod, '.'),
+		lex.NewItem(21, tokenPeriod, '.'),
+		lex.NewItem(22, tokenIdent, 'h'),
+		lex.NewItem(23, tokenEllipsis, []rune("...")...),
+		lex.NewItem(26, tokenShr, []rune(">>")...),
+		lex.NewItem[uint, rune](28, tokenEOF),
+	}
+
+	t.Run("Lex", func(t *testing.T) {
+		lextest.Compare(t, wants, lex.Collect[uint, rune](lex.New(scanState(operators.Scan), input)))
+	})
+	t.Run("LexBuffer", func(t *testing.T) {
+		l, err := lex.NewLexBuffer(scanState(operators.Scan), (gio.Reader[rune])(gbuf.NewReader(input)), lex.WithBufferCap(2))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		lextest.Compare(t, wants, lex.Collect[uint, rune](l))
+	})
+}
+
+func TestOperatorsMatch(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		input string
+		token uint
+		ok    bool
+		pos   int
+	}{
+		{"Longest", ">>=", tokenShrAssign, true, 3},
+		{"Shorter", ">>x", tokenShr, true, 2},
+		{"Backtrack", "..x", 0, false, 0},
+		{"BacktrackToMatch", ">>.", tokenShr, true, 2},
+		{"NoMatch", "x", 0, false, 0},
+		{"Empty", "", 0, false, 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			l := lex.New(scanState(operators.Scan), []rune(test.input))
+			token, ok := operators.Match(l)
+			if token != test.token || ok != test.ok || l.Pos() != test.pos {
+				t.Errorf("unexpected match: wanted %d %v on position %d ; got %d %v on position %d", test.token, test.ok, test.pos, token, ok, l.Pos())
+			}
+		})
+	}
+}
+
+func BenchmarkOperators(b *testing.B) {
+	input := []rune(">>= >= >> > == = != ... >>= >= >> > == = != ...")
+	seqs := [][]rune{[]rune(">"), []rune(">>"), []rune(">>="), []rune(">="), []rune("="), []rune("=="), []rune("!="), []rune("...")}
+	types := []uint{tokenGT, tokenShr, tokenShrAssign, tokenGE, tokenAssign, tokenEQ, tokenNE, tokenEllipsis}
+
+	run := func(b *testing.B, state lex.StateFn[uint, rune]) {
+		var item lex.Item[uint, rune]
+		l := lex.New(state, input)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			l.Reset(state, input)
+			for item = l.NextItem(); item.Type != tokenEOF; item = l.NextItem() {
+			}
+		}
+		_ = item
+	}
+
+	var trieState lex.StateFn[uint, rune]
+	trieState = func(l lex.Lexer[uint, rune]) lex.StateFn[uint, rune] {
+		l.AcceptRun(func(item rune) bool { return item == ' ' })
+		l.Ignore()
+		if !operators.Scan(l) {
+			l.Emit(tokenEOF)
+			return nil
+		}
+		return trieState
+	}
+
+	var acceptState lex.StateFn[uint, rune]
+	acceptState = func(l lex.Lexer[uint, rune]) lex.StateFn[uint, rune] {
+		l.AcceptRun(func(item rune) bool { return item == ' ' })
+		l.Ignore()
+		idx := lex.AcceptAny(l, seqs)
+		if idx < 0 {
+			l.Emit(tokenEOF)
+			return nil
+		}
+		l.Emit(types[idx])
+		return acceptState
+	}
+
+	b.Run("Operators", func(b *testing.B) {
+		run(b, trieState)
+	})
+	b.Run("AcceptAny", func(b *testing.B) {
+		run(b, acceptState)
+	})
+}
@@ -0,0 +1,182 @@
+package lex
+
+import (
+	"errors"
+	"fmt"
+)
+
+const defaultQueueSize = 2
+
+var (
+	// ErrNilReader is a preset error for buffered lexers created with a nil gio.Reader
+	ErrNilReader = errors.New("input reader cannot be nil")
+	// ErrInvalidOption is a preset error for Options with invalid values
+	ErrInvalidOption = errors.New("invalid option")
+)
+
+// Tracer describes the behavior of an object that observes a lexer's execution, such as for
+// debugging a set of StateFns
+type Tracer[C comparable, T any] interface {
+	// State is called before running each StateFn, with its name and the lexer's position
+	State(name string, pos int)
+	// Item is called with each item returned by the lexer's `NextItem()` method
+	Item(item Item[C, T])
+}
+
+// Option configures a lexer created with NewLex or NewLexBuffer
+//
+// Options that only apply to a LexBuffer (such as its buffer's capacity) are ignored by a Lex
+type Option interface {
+	apply(o *options)
+}
+
+type optionFunc func(o *options)
+
+func (fn optionFunc) apply(o *options) {
+	fn(o)
+}
+
+type options struct {
+	queueSize   int
+	bufferCap   int
+	bufferThres int
+	lookback    int
+	eof         any
+	tracer      any
+	limits      Limits
+	recovery    bool
+	err         error
+}
+
+func newOptions(opts []Option) (options, error) {
+	o := options{
+		queueSize:   defaultQueueSize,
+		bufferCap:   bufferInitCap,
+		bufferThres: bufferCapThres,
+		lookback:    bufferLookbackSize,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt.apply(&o)
+		if o.err != nil {
+			return o, o.err
+		}
+	}
+	return o, nil
+}
+
+// invalid returns an optionFunc that fails with an error wrapping ErrInvalidOption for the
+// option `name` with the value `value`
+func invalid(name string, value any) optionFunc {
+	return func(o *options) {
+		o.err = fmt.Errorf("%w: %s cannot be %v", ErrInvalidOption, name, value)
+	}
+}
+
+// WithQueueSize sets the capacity of the lexer's items queue, which bounds the number of items
+// that a single StateFn can emit (defaults to 2)
+func WithQueueSize(size int) Option {
+	if size < 1 {
+		return invalid("queue size", size)
+	}
+	return optionFunc(func(o *options) {
+		o.queueSize = size
+	})
+}
+
+// WithBufferCap sets the initial capacity of a LexBuffer's buffer (defaults to 1024)
+func WithBufferCap(size int) Option {
+	if size < 1 {
+		return invalid("buffer capacity", size)
+	}
+	return optionFunc(func(o *options) {
+		o.bufferCap = size
+	})
+}
+
+// WithBufferThreshold sets the remaining capacity in a LexBuffer's buffer below which a new
+// buffer is allocated, when an item is emitted (defaults to 96)
+func WithBufferThreshold(size int) Option {
+	if size < 0 {
+		return invalid("buffer threshold", size)
+	}
+	return optionFunc(func(o *options) {
+		o.bufferThres = size
+	})
+}
+
+// WithLookback sets the number of units that a LexBuffer keeps before its position when an
+// item is emitted (defaults to 5), as with the LexBuffer's `Size()` method
+func WithLookback(size int) Option {
+	if size < 0 {
+		return invalid("look-back size", size)
+	}
+	return optionFunc(func(o *options) {
+		o.lookback = size
+	})
+}
+
+// WithEOF sets the EOF unit returned by the lexer's cursor methods when out of the bounds of
+// the input (defaults to the zero value of T)
+//
+// StateFns that detect the end of the input by comparing units must compare them against this
+// unit instead of the zero value, otherwise they will keep reading EOF units without ever
+// stopping; and the same applies to the StateFns in this repository's examples, which compare
+// against zero. The Indent component and the Accept methods detect the end of the input by the
+// cursor's position, so they are not affected
+//
+// The type T must match the lexer's unit type, otherwise the constructor returns an error
+// wrapping ErrInvalidOption
+func WithEOF[T any](eof T) Option {
+	return optionFunc(func(o *options) {
+		o.eof = eof
+	})
+}
+
+// WithTracer sets a Tracer to observe the lexer's StateFns and items
+//
+// The types C and T must match the lexer's types, otherwise the constructor returns an error
+// wrapping ErrInvalidOption
+func WithTracer[C comparable, T any](tracer Tracer[C, T]) Option {
+	if tracer == nil {
+		return invalid("tracer", tracer)
+	}
+	return optionFunc(func(o *options) {
+		o.tracer = tracer
+	})
+}
+
+// WithLimits sets the runtime limits for the lexer, as with the lexer's `Limit()` method
+func WithLimits(limits Limits) Option {
+	return optionFunc(func(o *options) {
+		o.limits = limits
+	})
+}
+
+// WithRecover enables the recovery of panics raised by the StateFns, as with the lexer's
+// `Recover()` method
+func WithRecover() Option {
+	return optionFunc(func(o *options) {
+		o.recovery = true
+	})
+}
+
+// typed returns the EOF unit and Tracer set in the options `o`, verifying that they match
+// the lexer's types
+func typed[C comparable, T any](o options) (eof T, tracer Tracer[C, T], err error) {
+	if o.eof != nil {
+		var ok bool
+		if eof, ok = o.eof.(T); !ok {
+			return eof, nil, fmt.Errorf("%w: EOF unit of type %T does not match the lexer's unit type %T", ErrInvalidOption, o.eof, eof)
+		}
+	}
+	if o.tracer != nil {
+		var ok bool
+		if tracer, ok = o.tracer.(Tracer[C, T]); !ok {
+			return eof, nil, fmt.Errorf("%w: tracer of type %T does not match the lexer's types", ErrInvalidOption, o.tracer)
+		}
+	}
+	return eof, tracer, nil
+}
@@ -0,0 +1,129 @@
+package lex_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zalgonoise/gbuf"
+	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+)
+
+type testTracer struct {
+	states []string
+	items  []lex.Item[uint, rune]
+}
+
+func (t *testTracer) State(name string, pos int) {
+	t.states = append(t.states, name)
+}
+
+func (t *testTracer) Item(item lex.Item[uint, rune]) {
+	t.items = append(t.items, item)
+}
+
+// eofState describes a StateFn that emits the whole input as a single ident item, stopping on
+// the `$` EOF unit
+func eofState[C uint, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	for l.Next() != '$' {
+	}
+	l.Emit((C)(tokenIdent))
+	l.Emit((C)(tokenEOF))
+	return nil
+}
+
+// burstState describes a StateFn that emits each unit in the input as an ident item, all at once
+func burstState[C uint, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	for l.Accept(func(T) bool { return true }) {
+		l.Emit((C)(tokenIdent))
+	}
+	l.Emit((C)(tokenEOF))
+	return nil
+}
+
+func TestOptions(t *testing.T) {
+	t.Run("EmptyInput", func(t *testing.T) {
+		l, err := lex.NewLex(initState[uint, rune], []rune{})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if i := l.NextItem(); i.Type != tokenEOF || i.Pos != 0 || len(i.Value) != 0 {
+			t.Errorf("unexpected item: wanted a plain EOF item ; got %v", i)
+		}
+		if l.Head() != 0 || l.Tail() != 0 {
+			t.Errorf("unexpected units on an empty input")
+		}
+		if lex.New(initState[uint, rune], nil) == nil {
+			t.Errorf("output lexer should not be nil")
+		}
+	})
+
+	t.Run("NilReader", func(t *testing.T) {
+		if _, err := lex.NewLexBuffer[uint, rune](initState[uint, rune], nil); !errors.Is(err, lex.ErrNilReader) {
+			t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrNilReader, err)
+		}
+	})
+
+	t.Run("InvalidOptions", func(t *testing.T) {
+		for _, opt := range []lex.Option{
+			lex.WithQueueSize(0),
+			lex.WithBufferCap(-1),
+			lex.WithLookback(-1),
+			lex.WithEOF[byte](0),
+			lex.WithTracer[uint, byte](nil),
+		} {
+			if _, err := lex.NewLex(initState[uint, rune], testInput1, opt); !errors.Is(err, lex.ErrInvalidOption) {
+				t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrInvalidOption, err)
+			}
+		}
+	})
+
+	t.Run("QueueSize", func(t *testing.T) {
+		l, err := lex.NewLex(burstState[uint, rune], testInput1, lex.WithQueueSize(len(testInput1)+1))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if items := lex.Collect[uint, rune](l); len(items) != len(testInput1)+1 {
+			t.Errorf("token slice length mismatch error: wanted %d ; got %d", len(testInput1)+1, len(items))
+		}
+	})
+
+	t.Run("EOFAndTracer", func(t *testing.T) {
+		tracer := &testTracer{}
+		l, err := lex.NewLexBuffer(eofState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(testInput2)),
+			lex.WithEOF[rune]('$'),
+			lex.WithTracer[uint, rune](tracer),
+			lex.WithBufferCap(4),
+			lex.WithBufferThreshold(2),
+			lex.WithLookback(1),
+		)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		items := lex.Collect[uint, rune](l)
+		if len(tracer.items) != len(items) {
+			t.Errorf("traced items length mismatch error: wanted %d ; got %d", len(items), len(tracer.items))
+		}
+		if len(tracer.states) == 0 || !strings.Contains(tracer.states[0], "eofState") {
+			t.Errorf("unexpected traced states: %v", tracer.states)
+		}
+		if r := l.Next(); r != '$' {
+			t.Errorf("unexpected EOF unit: wanted %q ; got %q", '$', r)
+		}
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		l, err := lex.NewLex(stuckState[uint, rune], testInput1, lex.WithLimits(lex.Limits{MaxStall: 4}), lex.WithRecover())
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		if i := l.NextItem(); !errors.Is(i.Err, lex.ErrNoProgress) {
+			t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrNoProgress, i.Err)
+		}
+	})
+}
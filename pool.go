@@ -0,0 +1,111 @@
+package lex
+
+import (
+	"sync"
+
+	"github.com/zalgonoise/gio"
+)
+
+// Pool is a typed pool of lexers, that reuses their items queue across inputs (with their
+// `Reset()` method), so that lexing many small inputs does not allocate a new lexer for each
+//
+// All the lexers in a Pool are created with the same Options. A Pool is safe for concurrent use,
+// while the lexers it returns are not
+//
+// Note that StateFns returning instances of generic functions (such as `initState[C, T]`) allocate
+// on each transition; use non-generic StateFns for zero-allocation lexing
+type Pool[C comparable, T any] struct {
+	pool sync.Pool
+}
+
+// NewPool creates a Pool of lexers configured with the input Options `opts`
+//
+// It returns an error if any of the Options is invalid
+func NewPool[C comparable, T any](opts ...Option) (*Pool[C, T], error) {
+	if _, err := NewLex[C, T](nil, nil, opts...); err != nil {
+		return nil, err
+	}
+
+	return &Pool[C, T]{
+		pool: sync.Pool{
+			New: func() any {
+				l, _ := NewLex[C, T](nil, nil, opts...)
+				return l
+			},
+		},
+	}, nil
+}
+
+// Get returns a lexer from the pool (or a new one, if the pool is empty), set up to lex the
+// input data `input` with the base / starting StateFn `initFn`
+func (p *Pool[C, T]) Get(initFn StateFn[C, T], input []T) *Lex[C, T] {
+	l := p.pool.Get().(*Lex[C, T])
+	l.Reset(initFn, input)
+	return l
+}
+
+// Put returns the lexer `l` to the pool, once it is no longer used
+//
+// The lexer must have been created by the same pool, or with the same Options
+func (p *Pool[C, T]) Put(l *Lex[C, T]) {
+	if l == nil {
+		return
+	}
+	// release the references to the input data and StateFns
+	l.Reset(nil, nil)
+	p.pool.Put(l)
+}
+
+// BufferPool is a typed pool of buffered lexers, that reuses their items queue and buffer across
+// inputs (with their `Reset()` method), so that lexing many small inputs does not allocate a new
+// lexer for each
+//
+// All the lexers in a BufferPool are created with the same Options. A BufferPool is safe for
+// concurrent use, while the lexers it returns are not
+type BufferPool[C comparable, T any] struct {
+	pool sync.Pool
+}
+
+// NewBufferPool creates a BufferPool of buffered lexers configured with the input Options `opts`
+//
+// It returns an error if any of the Options is invalid
+func NewBufferPool[C comparable, T any](opts ...Option) (*BufferPool[C, T], error) {
+	if _, err := newLexBuffer[C, T](nil, nil, opts); err != nil {
+		return nil, err
+	}
+
+	return &BufferPool[C, T]{
+		pool: sync.Pool{
+			New: func() any {
+				l, _ := newLexBuffer[C, T](nil, nil, opts)
+				return l
+			},
+		},
+	}, nil
+}
+
+// Get returns a buffered lexer from the pool (or a new one, if the pool is empty), set up to lex
+// the input gio.Reader `input` with the base / starting StateFn `initFn`
+//
+// It returns ErrNilReader if the input gio.Reader is nil
+func (p *BufferPool[C, T]) Get(initFn StateFn[C, T], input gio.Reader[T]) (*LexBuffer[C, T], error) {
+	if input == nil {
+		return nil, ErrNilReader
+	}
+	l := p.pool.Get().(*LexBuffer[C, T])
+	l.reset(initFn, input)
+	return l, nil
+}
+
+// Put returns the buffered lexer `l` to the pool, once it is no longer used; after which the
+// values of the items it returned must not be used, as its buffer is reused
+//
+// The lexer must have been created by the same pool, or with the same Options
+func (p *BufferPool[C, T]) Put(l *LexBuffer[C, T]) {
+	if l == nil {
+		return
+	}
+	// release the references to the input gio.Reader and StateFns
+	l.reset(nil, nil)
+	p.pool.Put(l)
+}
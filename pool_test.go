@@ -0,0 +1,144 @@
+package lex_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zalgonoise/gbuf"
+	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+	"github.com/zalgonoise/lex/lextest"
+)
+
+// drainItems consumes all items from the Emitter `e`, until an EOF item is returned
+func drainItems(e lex.Emitter[uint, rune]) {
+	for e.NextItem().Type != tokenEOF {
+	}
+}
+
+func TestReset(t *testing.T) {
+	t.Run("Lex", func(t *testing.T) {
+		l := lex.New(stuckState[uint, rune], testInput1)
+		l.Route(tokenPeriod, lex.HiddenChannel)
+		if item := l.NextItem(); !errors.Is(item.Err, lex.ErrNoProgress) {
+			t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrNoProgress, item.Err)
+		}
+
+		for _, input := range [][]rune{testInput2, testInput3, {}} {
+			l.Reset(initState[uint, rune], input)
+			got := lex.Collect[uint, rune](l)
+
+			wants := lex.Collect[uint, rune](lex.Filter[uint, rune](
+				lex.New(initState[uint, rune], input),
+				func(item lex.Item[uint, rune]) bool { return item.Type != tokenPeriod },
+			))
+			lextest.Compare(t, wants, got)
+		}
+	})
+
+	t.Run("LexBuffer", func(t *testing.T) {
+		l := lex.NewBuffer(initState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(testInput3)))
+		drainItems(l)
+
+		for _, input := range [][]rune{testInput1, testInput2, {}} {
+			if err := l.Reset(initState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(input))); err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			lextest.Compare(t, lextest.Lex(initState[uint, rune], input), lex.Collect[uint, rune](l))
+		}
+
+		if err := l.Reset(initState[uint, rune], nil); !errors.Is(err, lex.ErrNilReader) {
+			t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrNilReader, err)
+		}
+	})
+}
+
+func TestPool(t *testing.T) {
+	t.Run("Lex", func(t *testing.T) {
+		pool, err := lex.NewPool[uint, rune](lex.WithQueueSize(4))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		for _, input := range [][]rune{testInput1, testInput2, testInput3} {
+			l := pool.Get(initState[uint, rune], input)
+			lextest.Compare(t, lextest.Lex(initState[uint, rune], input), lex.Collect[uint, rune](l))
+			pool.Put(l)
+		}
+
+		if _, err := lex.NewPool[uint, rune](lex.WithQueueSize(0)); !errors.Is(err, lex.ErrInvalidOption) {
+			t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrInvalidOption, err)
+		}
+	})
+
+	t.Run("LexBuffer", func(t *testing.T) {
+		pool, err := lex.NewBufferPool[uint, rune](lex.WithBufferCap(16))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		for _, input := range [][]rune{testInput1, testInput2, testInput3} {
+			l, err := pool.Get(initState[uint, rune], (gio.Reader[rune])(gbuf.NewReader(input)))
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			lextest.Compare(t, lextest.Lex(initState[uint, rune], input), lex.Collect[uint, rune](l))
+			pool.Put(l)
+		}
+
+		if _, err := pool.Get(initState[uint, rune], nil); !errors.Is(err, lex.ErrNilReader) {
+			t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrNilReader, err)
+		}
+		if _, err := lex.NewBufferPool[uint, rune](lex.WithBufferCap(0)); !errors.Is(err, lex.ErrInvalidOption) {
+			t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrInvalidOption, err)
+		}
+	})
+}
+
+// periodState describes a (non-generic) StateFn that emits each word in the input as an ident
+// item, and each period as a period item
+func periodState(l lex.Lexer[uint, rune]) lex.StateFn[uint, rune] {
+	switch l.Cur() {
+	case 0:
+		l.Emit(tokenEOF)
+		return nil
+	case '.':
+		l.Next()
+		l.Emit(tokenPeriod)
+	default:
+		l.AcceptRun(func(item rune) bool { return item != '.' && item != 0 })
+		l.Emit(tokenIdent)
+	}
+	return periodState
+}
+
+func TestResetAllocs(t *testing.T) {
+	t.Run("Lex", func(t *testing.T) {
+		l := lex.New(periodState, testInput1)
+		drainItems(l)
+
+		if allocs := testing.AllocsPerRun(100, func() {
+			l.Reset(periodState, testInput2)
+			drainItems(l)
+		}); allocs != 0 {
+			t.Errorf("unexpected allocations: wanted %d ; got %v", 0, allocs)
+		}
+	})
+
+	t.Run("LexBuffer", func(t *testing.T) {
+		reader := gbuf.NewReader(testInput1)
+		input := (gio.Reader[rune])(reader)
+		l := lex.NewBuffer(periodState, input)
+		drainItems(l)
+
+		if allocs := testing.AllocsPerRun(100, func() {
+			reader.Reset(testInput2)
+			_ = l.Reset(periodState, input)
+			drainItems(l)
+		}); allocs != 0 {
+			t.Errorf("unexpected allocations: wanted %d ; got %v", 0, allocs)
+		}
+	})
+}
@@ -0,0 +1,307 @@
+package lex
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zalgonoise/lex/class"
+)
+
+var (
+	// ErrUnterminatedString is a preset error for string literals missing their closing quote,
+	// before the end of the input (or the end of the line, if they cannot span multiple lines)
+	ErrUnterminatedString = errors.New("unterminated string literal")
+	// ErrInvalidEscape is a preset error for string literals with an escape sequence that is not
+	// valid in their EscapeGrammar
+	ErrInvalidEscape = errors.New("invalid escape sequence")
+)
+
+// EscapeGrammar defines the escape sequences accepted in the string literals scanned by a Quoted
+type EscapeGrammar uint8
+
+const (
+	// EscapeNone accepts no escape sequences: backslashes are read as any other unit
+	EscapeNone EscapeGrammar = iota
+	// EscapeGo accepts Go's escape sequences: `\a \b \f \n \r \t \v \\ \' \"`, octal bytes as
+	// `\ooo`, hexadecimal bytes as `\xhh`, and Unicode code points as `\uhhhh` and `\Uhhhhhhhh`
+	EscapeGo
+	// EscapeJSON accepts JSON's escape sequences: `\" \\ \/ \b \f \n \r \t` and UTF-16 code units
+	// as `\uhhhh` (combining surrogate pairs)
+	EscapeJSON
+	// EscapeC accepts C's escape sequences: `\a \b \f \n \r \t \v \\ \' \" \?`, octal bytes as
+	// `\o` up to `\ooo`, hexadecimal bytes as `\xh` or `\xhh`, and Unicode code points as `\uhhhh`
+	// and `\Uhhhhhhhh`
+	EscapeC
+	// EscapeDoubled accepts a doubled quote as an escaped quote, like in SQL (`'it''s'`), while
+	// backslashes are read as any other unit
+	EscapeDoubled
+)
+
+// QuotedTokens describes the token types emitted by a Quoted
+type QuotedTokens[C comparable] struct {
+	// String is the token type for string literals, whose Data is the decoded string value
+	String C
+	// Error is the token type for the error items, on unterminated strings or invalid escape
+	// sequences
+	Error C
+}
+
+// Quoted scans quoted string literals in byte or rune lexers, following a configurable set of
+// quotes and EscapeGrammar; as a fragment to be called from a StateFn, with its `Scan()` method
+//
+// The literals are decoded into a (UTF-8) string, set as the emitted item's Data (if the lexer is
+// a ValueEmitter); while the item's value holds the literal's units as they are in the input,
+// including its quotes. By default, only double-quoted strings are scanned, and they cannot span
+// multiple lines
+type Quoted[C comparable, T class.Char] struct {
+	tokens    QuotedTokens[C]
+	escape    EscapeGrammar
+	quotes    []T
+	raw       []T
+	multiLine bool
+}
+
+// NewQuoted creates a Quoted emitting the token types in `tokens`, for double-quoted string
+// literals with the escape sequences in the EscapeGrammar `escape`
+func NewQuoted[C comparable, T class.Char](tokens QuotedTokens[C], escape EscapeGrammar) *Quoted[C, T] {
+	return &Quoted[C, T]{
+		tokens: tokens,
+		escape: escape,
+		quotes: []T{'"'},
+	}
+}
+
+// Quotes sets the quote units for the (interpreted) string literals, each of them closing the
+// literals it opens
+func (q *Quoted[C, T]) Quotes(quotes ...T) {
+	q.quotes = quotes
+}
+
+// Raw sets the quote units for raw string literals (like Go's backtick strings), each of them
+// closing the literals it opens; which accept no escape sequences, and can always span
+// multiple lines
+func (q *Quoted[C, T]) Raw(quotes ...T) {
+	q.raw = quotes
+}
+
+// MultiLine sets whether the (interpreted) string literals can span multiple lines. If not,
+// a newline before the closing quote is reported as an unterminated string
+func (q *Quoted[C, T]) MultiLine(enabled bool) {
+	q.multiLine = enabled
+}
+
+// Scan lexes the string literal on the lexer's cursor, emitting it as a String item holding its
+// decoded value, and returns true. The lexer's starting point must be on its cursor (with no
+// units pending to be emitted or ignored)
+//
+// Unterminated strings are emitted as an Error item wrapping ErrUnterminatedString, positioned on
+// their opening quote and spanning up to the end of the input (or of the line); while strings with
+// invalid escape sequences are emitted whole as an Error item wrapping ErrInvalidEscape
+//
+// If the cursor is not on an opening quote, nothing is consumed and false is returned
+func (q *Quoted[C, T]) Scan(l Lexer[C, T]) bool {
+	quote := l.Cur()
+	raw := contains(q.raw, quote)
+	if !raw && !contains(q.quotes, quote) {
+		return false
+	}
+	l.Next()
+
+	var (
+		sb  = new(strings.Builder)
+		err error
+	)
+	for {
+		pos := l.Pos()
+		unit := l.Next()
+		switch {
+		case l.Pos() == pos:
+			// EOF
+			emitError(l, q.tokens.Error, ErrUnterminatedString)
+			return true
+		case unit == quote:
+			if !raw && q.escape == EscapeDoubled && l.Cur() == quote {
+				l.Next()
+				writeUnit(sb, unit)
+				continue
+			}
+			if err != nil {
+				emitError(l, q.tokens.Error, err)
+				return true
+			}
+			emitData(l, q.tokens.String, sb.String())
+			return true
+		case unit == '\n' && !raw && !q.multiLine:
+			l.Prev()
+			emitError(l, q.tokens.Error, ErrUnterminatedString)
+			return true
+		case unit == '\\' && !raw && q.escape != EscapeNone && q.escape != EscapeDoubled:
+			if l.Cur() == '\n' {
+				// the newline is read on the next iteration
+				if err == nil {
+					err = fmt.Errorf("%w: \\ before a newline", ErrInvalidEscape)
+				}
+				continue
+			}
+			if escErr := q.unescape(l, sb); escErr != nil && err == nil {
+				err = escErr
+			}
+		default:
+			writeUnit(sb, unit)
+		}
+	}
+}
+
+// unescape decodes the escape sequence following a backslash, in the Quoted's EscapeGrammar,
+// writing it to the strings.Builder `sb`
+//
+// It returns an error wrapping ErrInvalidEscape if the escape sequence is not valid
+func (q *Quoted[C, T]) unescape(l Lexer[C, T], sb *strings.Builder) error {
+	unit := l.Next()
+	if r, ok := simpleEscape(q.escape, unit); ok {
+		sb.WriteRune(r)
+		return nil
+	}
+
+	switch {
+	case unit >= '0' && unit <= '7' && (q.escape == EscapeGo || q.escape == EscapeC):
+		value := int(unit - '0')
+		count := 1
+		for ; count < 3 && l.Cur() >= '0' && l.Cur() <= '7'; count++ {
+			value = value*8 + int(l.Next()-'0')
+		}
+		if value > 0xFF || (q.escape == EscapeGo && count < 3) {
+			return fmt.Errorf("%w: octal value out of range", ErrInvalidEscape)
+		}
+		sb.WriteByte(byte(value))
+		return nil
+	case unit == 'x' && (q.escape == EscapeGo || q.escape == EscapeC):
+		size := 2
+		if q.escape == EscapeC && !isDigit(l.PeekOffset(1), 16) {
+			size = 1
+		}
+		value, ok := hexValue(l, size)
+		if !ok {
+			return fmt.Errorf("%w: \\x without hexadecimal digits", ErrInvalidEscape)
+		}
+		sb.WriteByte(byte(value))
+		return nil
+	case unit == 'u' || (unit == 'U' && q.escape != EscapeJSON):
+		size := 4
+		if unit == 'U' {
+			size = 8
+		}
+		value, ok := hexValue(l, size)
+		if !ok {
+			return fmt.Errorf("%w: \\%c without %d hexadecimal digits", ErrInvalidEscape, rune(unit), size)
+		}
+		r := rune(value)
+		if q.escape == EscapeJSON && utf16High(r) && l.Cur() == '\\' && l.PeekOffset(1) == 'u' {
+			// surrogate pair
+			pos := l.Pos()
+			l.Next()
+			l.Next()
+			if low, ok := hexValue(l, 4); ok && utf16Low(rune(low)) {
+				r = (r-0xD800)<<10 + (rune(low) - 0xDC00) + 0x10000
+			} else {
+				l.Idx(pos)
+			}
+		}
+		if q.escape != EscapeJSON && !utf8.ValidRune(r) {
+			return fmt.Errorf("%w: invalid Unicode code point %U", ErrInvalidEscape, r)
+		}
+		sb.WriteRune(r)
+		return nil
+	}
+	return fmt.Errorf("%w: \\%c", ErrInvalidEscape, rune(unit))
+}
+
+// simpleEscape returns the rune for the single-unit escape sequence `unit` in the EscapeGrammar
+// `escape`, and true; or false if it is not one
+func simpleEscape[T class.Char](escape EscapeGrammar, unit T) (rune, bool) {
+	switch unit {
+	case 'b':
+		return '\b', true
+	case 'f':
+		return '\f', true
+	case 'n':
+		return '\n', true
+	case 'r':
+		return '\r', true
+	case 't':
+		return '\t', true
+	case '\\':
+		return '\\', true
+	case '"':
+		return '"', true
+	case 'a':
+		return '\a', escape != EscapeJSON
+	case 'v':
+		return '\v', escape != EscapeJSON
+	case '\'':
+		return '\'', escape != EscapeJSON
+	case '/':
+		return '/', escape == EscapeJSON
+	case '?':
+		return '?', escape == EscapeC
+	}
+	return 0, false
+}
+
+// hexValue consumes exactly `size` hexadecimal digits, returning their value and true; or false
+// if any of them is not a hexadecimal digit, in which case nothing is consumed
+func hexValue[C comparable, T class.Char](l Lexer[C, T], size int) (uint32, bool) {
+	var value uint32
+	for idx := 0; idx < size; idx++ {
+		unit := l.PeekOffset(idx)
+		if !isDigit(unit, 16) {
+			return 0, false
+		}
+		switch {
+		case unit >= '0' && unit <= '9':
+			value = value<<4 | uint32(unit-'0')
+		case unit >= 'a' && unit <= 'f':
+			value = value<<4 | uint32(unit-'a'+10)
+		default:
+			value = value<<4 | uint32(unit-'A'+10)
+		}
+	}
+	for idx := 0; idx < size; idx++ {
+		l.Next()
+	}
+	return value, true
+}
+
+// utf16High returns true if the rune `r` is a high (leading) UTF-16 surrogate
+func utf16High(r rune) bool {
+	return r >= 0xD800 && r < 0xDC00
+}
+
+// utf16Low returns true if the rune `r` is a low (trailing) UTF-16 surrogate
+func utf16Low(r rune) bool {
+	return r >= 0xDC00 && r < 0xE000
+}
+
+// writeUnit writes the unit `unit` to the strings.Builder `sb`: as a byte (of UTF-8 encoded
+// text) for byte units, or as a rune for rune units
+func writeUnit[T class.Char](sb *strings.Builder, unit T) {
+	var zero T
+	if ^zero > 0 {
+		// unsigned: a byte type
+		sb.WriteByte(byte(unit))
+		return
+	}
+	sb.WriteRune(rune(unit))
+}
+
+// contains returns true if the units `units` include the unit `unit`
+func contains[T comparable](units []T, unit T) bool {
+	for _, u := range units {
+		if u == unit {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package lex_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zalgonoise/gbuf"
+	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+)
+
+var quotedTokens = lex.QuotedTokens[uint]{
+	String: tokenString,
+	Error:  tokenError,
+}
+
+func TestQuoted(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		escape lex.EscapeGrammar
+		setup  func(q quotedSetup)
+		input  string
+		token  uint
+		value  string
+		data   any
+		err    error
+	}{
+		{"Plain", lex.EscapeGo, nil, `"hello"`, tokenString, `"hello"`, "hello", nil},
+		{"Empty", lex.EscapeGo, nil, `""`, tokenString, `""`, "", nil},
+		{"Unicode", lex.EscapeGo, nil, `"héllo 世界"`, tokenString, `"héllo 世界"`, "héllo 世界", nil},
+		{"GoEscapes", lex.EscapeGo, nil, `"a\tb\n\"c\"\\\a"`, tokenString, `"a\tb\n\"c\"\\\a"`, "a\tb\n\"c\"\\\a", nil},
+		{"GoOctal", lex.EscapeGo, nil, `"\101\060"`, tokenString, `"\101\060"`, "A0", nil},
+		{"GoHex", lex.EscapeGo, nil, `"\x41\x7e"`, tokenString, `"\x41\x7e"`, "A~", nil},
+		{"GoUnicode", lex.EscapeGo, nil, `"\u00e9\U0001F600"`, tokenString, `"\u00e9\U0001F600"`, "é😀", nil},
+		{"JSONEscapes", lex.EscapeJSON, nil, `"a\/b\u0041"`, tokenString, `"a\/b\u0041"`, "a/bA", nil},
+		{"JSONSurrogates", lex.EscapeJSON, nil, `"\ud83d\ude00"`, tokenString, `"\ud83d\ude00"`, "\U0001F600", nil},
+		{"JSONLoneSurrogate", lex.EscapeJSON, nil, `"\ud83dx"`, tokenString, `"\ud83dx"`, "\uFFFDx", nil},
+		{"CEscapes", lex.EscapeC, nil, `"\?\1\x4"`, tokenString, `"\?\1\x4"`, "?\x01\x04", nil},
+		{"NoEscapes", lex.EscapeNone, nil, `"a\n"`, tokenString, `"a\n"`, `a\n`, nil},
+		{"Doubled", lex.EscapeDoubled, func(q quotedSetup) { q.Quotes('\'') }, `'it''s'`, tokenString, `'it''s'`, "it's", nil},
+		{"DoubledBackslash", lex.EscapeDoubled, nil, `"a\"`, tokenString, `"a\"`, `a\`, nil},
+		{"Quotes", lex.EscapeGo, func(q quotedSetup) { q.Quotes('"', '\'') }, `'say "hi"'`, tokenString, `'say "hi"'`, `say "hi"`, nil},
+		{"Raw", lex.EscapeGo, func(q quotedSetup) { q.Raw('`') }, "`a\\n\nb`", tokenString, "`a\\n\nb`", "a\\n\nb", nil},
+		{"MultiLine", lex.EscapeGo, func(q quotedSetup) { q.MultiLine(true) }, "\"a\nb\"", tokenString, "\"a\nb\"", "a\nb", nil},
+		{"NotQuoted", lex.EscapeGo, nil, `'a'`, tokenPeriod, `'`, nil, nil},
+
+		{"Unterminated", lex.EscapeGo, nil, `"abc`, tokenError, `"abc`, nil, lex.ErrUnterminatedString},
+		{"UnterminatedEscape", lex.EscapeGo, nil, `"abc\"`, tokenError, `"abc\"`, nil, lex.ErrUnterminatedString},
+		{"UnterminatedLine", lex.EscapeGo, nil, "\"abc\ndef\"", tokenError, `"abc`, nil, lex.ErrUnterminatedString},
+		{"UnterminatedRaw", lex.EscapeGo, func(q quotedSetup) { q.Raw('`') }, "`abc\n", tokenError, "`abc\n", nil, lex.ErrUnterminatedString},
+		{"EscapedNewline", lex.EscapeGo, nil, "\"abc\\\ndef\"", tokenError, "\"abc\\", nil, lex.ErrUnterminatedString},
+		{"InvalidEscape", lex.EscapeGo, nil, `"a\qb"`, tokenError, `"a\qb"`, nil, lex.ErrInvalidEscape},
+		{"JSONNoSingleQuote", lex.EscapeJSON, nil, `"\'"`, tokenError, `"\'"`, nil, lex.ErrInvalidEscape},
+		{"GoShortOctal", lex.EscapeGo, nil, `"\1"`, tokenError, `"\1"`, nil, lex.ErrInvalidEscape},
+		{"OctalRange", lex.EscapeGo, nil, `"\400"`, tokenError, `"\400"`, nil, lex.ErrInvalidEscape},
+		{"GoShortHex", lex.EscapeGo, nil, `"\x4"`, tokenError, `"\x4"`, nil, lex.ErrInvalidEscape},
+		{"ShortUnicode", lex.EscapeGo, nil, `"\u12"`, tokenError, `"\u12"`, nil, lex.ErrInvalidEscape},
+		{"InvalidCodePoint", lex.EscapeGo, nil, `"\ud800"`, tokenError, `"\ud800"`, nil, lex.ErrInvalidEscape},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			verify := func(t *testing.T, item lex.Item[uint, rune]) {
+				if item.Pos != 0 || item.Type != test.token || string(item.Value) != test.value {
+					t.Errorf("unexpected item: wanted %d %q on position %d ; got %v", test.token, test.value, 0, item)
+				}
+				if item.Data != test.data {
+					t.Errorf("unexpected data: wanted %q ; got %q", test.data, item.Data)
+				}
+				if !errors.Is(item.Err, test.err) || (test.err == nil) != (item.Err == nil) {
+					t.Errorf("unexpected error: wanted %v ; got %v", test.err, item.Err)
+				}
+			}
+
+			input := []rune(test.input)
+			q := lex.NewQuoted[uint, rune](quotedTokens, test.escape)
+			qb := lex.NewQuoted[uint, byte](quotedTokens, test.escape)
+			if test.setup != nil {
+				test.setup(q)
+				test.setup(quotedBytes{qb})
+			}
+
+			t.Run("Lex", func(t *testing.T) {
+				verify(t, lex.New(scanState(q.Scan), input).NextItem())
+			})
+			t.Run("LexBuffer", func(t *testing.T) {
+				verify(t, lex.NewBuffer(scanState(q.Scan), (gio.Reader[rune])(gbuf.NewReader(input))).NextItem())
+			})
+			t.Run("Bytes", func(t *testing.T) {
+				item := lex.New(scanState(qb.Scan), []byte(test.input)).NextItem()
+				verify(t, lex.Item[uint, rune]{Pos: item.Pos, Type: item.Type, Value: []rune(string(item.Value)), Err: item.Err, Data: item.Data})
+			})
+		})
+	}
+}
+
+// quotedSetup configures a Quoted over runes or bytes, with rune quotes
+type quotedSetup interface {
+	Quotes(quotes ...rune)
+	Raw(quotes ...rune)
+	MultiLine(enabled bool)
+}
+
+// quotedBytes adapts a Quoted over bytes to quotedSetup
+type quotedBytes struct {
+	*lex.Quoted[uint, byte]
+}
+
+func (q quotedBytes) Quotes(quotes ...rune) {
+	q.Quoted.Quotes([]byte(string(quotes))...)
+}
+
+func (q quotedBytes) Raw(quotes ...rune) {
+	q.Quoted.Raw([]byte(string(quotes))...)
+}
+
+func TestQuotedSequence(t *testing.T) {
+	q := lex.NewQuoted[uint, rune](quotedTokens, lex.EscapeGo)
+	q.Raw('`')
+	items := lex.Collect[uint, rune](lex.New(scanState(q.Scan), []rune("\"a\" . `b` \"c")))
+
+	wants := []struct {
+		pos   int
+		token uint
+		data  any
+		err   error
+	}{
+		{0, tokenString, "a", nil},
+		{4, tokenPeriod, nil, nil},
+		{6, tokenString, "b", nil},
+		{10, tokenError, nil, lex.ErrUnterminatedString},
+		{12, tokenEOF, nil, nil},
+	}
+	if len(items) != len(wants) {
+		t.Fatalf("unexpected items: wanted %d ; got %d: %v", len(wants), len(items), items)
+	}
+	for idx, want := range wants {
+		item := items[idx]
+		if item.Pos != want.pos || item.Type != want.token || item.Data != want.data || !errors.Is(item.Err, want.err) {
+			t.Errorf("unexpected item #%d: wanted %d on position %d ; got %v", idx, want.token, want.pos, item)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package lex
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError describes a panic recovered from a StateFn, when the lexer's panic recovery is
+// enabled, holding the recovered value and the stack trace for the panic
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+// Error implements the error interface
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in StateFn: %v", e.Value)
+}
+
+// Unwrap returns the recovered value if it is an error, or nil otherwise
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// run calls the StateFn `state` with the Lexer `l`, returning the next StateFn
+//
+// If `recovery` is set, a panic raised by the StateFn is recovered and returned as a *PanicError
+func run[C comparable, T any](l Lexer[C, T], state StateFn[C, T], recovery bool) (next StateFn[C, T], err error) {
+	if recovery {
+		defer func() {
+			if r := recover(); r != nil {
+				next = nil
+				err = &PanicError{
+					Value: r,
+					Stack: debug.Stack(),
+				}
+			}
+		}()
+	}
+	return state(l), nil
+}
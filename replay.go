@@ -0,0 +1,76 @@
+package lex
+
+// FromItems creates an Emitter that replays the input items `items` in order, and then
+// returns EOF items (with a zero-value token type) positioned after the last item
+//
+// The items are returned as-is, so it can be used to feed a parser with hand-built (or
+// malformed) token sequences, without running a lexer
+func FromItems[C comparable, T any](items ...Item[C, T]) Emitter[C, T] {
+	var idx int
+	var end int
+	if len(items) > 0 {
+		last := items[len(items)-1]
+		end = last.Pos + len(last.Value)
+	}
+	return EmitterFunc[C, T](func() Item[C, T] {
+		if idx >= len(items) {
+			return Item[C, T]{Pos: end}
+		}
+		idx++
+		return items[idx-1]
+	})
+}
+
+// Collect consumes all items from the Emitter `e`, up to and including the first EOF item
+// (with a zero-value token type), returning them as a slice
+func Collect[C comparable, T any](e Emitter[C, T]) []Item[C, T] {
+	var eof C
+	var items []Item[C, T]
+	for {
+		item := e.NextItem()
+		items = append(items, item)
+		if item.Type == eof {
+			return items
+		}
+	}
+}
+
+// Recorder wraps an Emitter, keeping a copy of every item it returns so that they can be
+// replayed later
+type Recorder[C comparable, T any] struct {
+	emitter Emitter[C, T]
+	items   []Item[C, T]
+}
+
+var _ Emitter[uint8, any] = &Recorder[uint8, any]{}
+
+// Record creates a Recorder for the input Emitter `e`
+func Record[C comparable, T any](e Emitter[C, T]) *Recorder[C, T] {
+	return &Recorder[C, T]{
+		emitter: e,
+	}
+}
+
+// NextItem implements the Emitter interface, by returning (and recording) the next item from
+// the underlying Emitter
+func (r *Recorder[C, T]) NextItem() Item[C, T] {
+	item := r.emitter.NextItem()
+	value := make([]T, len(item.Value))
+	copy(value, item.Value)
+	item.Value = value
+
+	r.items = append(r.items, item)
+	return item
+}
+
+// Items returns the items recorded so far
+func (r *Recorder[C, T]) Items() []Item[C, T] {
+	return r.items
+}
+
+// Replay returns an Emitter that replays the items recorded so far, as with FromItems
+func (r *Recorder[C, T]) Replay() Emitter[C, T] {
+	items := make([]Item[C, T], len(r.items))
+	copy(items, r.items)
+	return FromItems(items...)
+}
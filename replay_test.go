@@ -0,0 +1,44 @@
+package lex_test
+
+import (
+	"testing"
+
+	"github.com/zalgonoise/lex"
+)
+
+func TestReplay(t *testing.T) {
+	t.Run("FromItems", func(t *testing.T) {
+		e := lex.FromItems(
+			lex.NewItem(0, tokenPeriod, '.'),
+			lex.NewItem(1, tokenIdent, 'a', 'b'),
+		)
+		items := lex.Collect(e)
+		if len(items) != 3 {
+			t.Errorf("token slice length mismatch error: wanted %d ; got %d", 3, len(items))
+			return
+		}
+		if items[2].Type != tokenEOF || items[2].Pos != 3 {
+			t.Errorf("unexpected EOF item: wanted position %d ; got %d on position %d", 3, items[2].Type, items[2].Pos)
+		}
+		if i := e.NextItem(); i.Type != tokenEOF {
+			t.Errorf("unexpected token type: wanted %d ; got %d", tokenEOF, i.Type)
+		}
+	})
+
+	t.Run("Record", func(t *testing.T) {
+		// input: `lexing.data.`
+		r := lex.Record[uint, rune](lex.New(initState[uint, rune], testInput2))
+		wants := lex.Collect[uint, rune](r)
+		got := lex.Collect(r.Replay())
+
+		if len(got) != len(wants) || len(r.Items()) != len(wants) {
+			t.Errorf("token slice length mismatch error: wanted %d ; got %d", len(wants), len(got))
+			return
+		}
+		for idx := range wants {
+			if wants[idx].Pos != got[idx].Pos || wants[idx].Type != got[idx].Type || string(wants[idx].Value) != string(got[idx].Value) {
+				t.Errorf("unexpected item #%d: wanted %v ; got %v", idx, wants[idx], got[idx])
+			}
+		}
+	})
+}
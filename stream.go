@@ -0,0 +1,244 @@
+package lex
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnexpectedItem is a preset error for items that do not match the expected token types
+	ErrUnexpectedItem = errors.New("unexpected item")
+	// ErrInvalidMark is a preset error for TokenStream marks that are not active, or that point to
+	// items no longer buffered in the stream
+	ErrInvalidMark = errors.New("invalid stream mark")
+)
+
+// UnexpectedItemError is returned by a TokenStream's `Expect()` method when the next item
+// is not of any of the expected token types
+//
+// It wraps ErrUnexpectedItem, so it can be checked with `errors.Is()`
+type UnexpectedItemError[C comparable, T any] struct {
+	Item     Item[C, T]
+	Expected []C
+}
+
+// Error implements the error interface
+func (e *UnexpectedItemError[C, T]) Error() string {
+	return fmt.Sprintf("%s on position %d: got token %v ; wanted one of %v",
+		ErrUnexpectedItem.Error(), e.Item.Pos, e.Item.Type, e.Expected,
+	)
+}
+
+// Unwrap returns the underlying ErrUnexpectedItem error
+func (e *UnexpectedItemError[C, T]) Unwrap() error {
+	return ErrUnexpectedItem
+}
+
+// TokenStream wraps an Emitter with an item buffer, exposing an arbitrary amount of lookahead,
+// type expectations and backtracking for hand-written (recursive descent) parsers
+//
+// Items are pulled from the Emitter as needed. Once the Emitter returns an EOF item (one with a
+// zero-value token type), it is no longer called, and any further reads return that same EOF item.
+//
+// A TokenStream is also an Emitter, so it can be placed in front of a parse.Tree
+type TokenStream[C comparable, T any] struct {
+	emitter Emitter[C, T]
+	items   []Item[C, T]
+	pos     int
+	base    int
+	marks   []int
+	done    bool
+	eof     Item[C, T]
+}
+
+var _ Emitter[uint8, any] = &TokenStream[uint8, any]{}
+
+// NewTokenStream creates a TokenStream reading items from the input Emitter `emitter`
+func NewTokenStream[C comparable, T any](emitter Emitter[C, T]) *TokenStream[C, T] {
+	return &TokenStream[C, T]{
+		emitter: emitter,
+		items:   make([]Item[C, T], 0, 8),
+	}
+}
+
+// fill pulls items from the Emitter until there are `n` items buffered ahead of the
+// stream's position, or until the Emitter returns an EOF item
+func (s *TokenStream[C, T]) fill(n int) {
+	var eof C
+	for !s.done && len(s.items)-s.pos < n {
+		item := s.emitter.NextItem()
+		if item.Type == eof {
+			s.done = true
+			s.eof = item
+			return
+		}
+		s.items = append(s.items, item)
+	}
+}
+
+// compact drops the consumed items from the buffer, up to the oldest active mark
+func (s *TokenStream[C, T]) compact() {
+	keep := s.pos
+	for _, mark := range s.marks {
+		if mark-s.base < keep {
+			keep = mark - s.base
+		}
+	}
+	if keep == 0 {
+		return
+	}
+	n := copy(s.items, s.items[keep:])
+	s.items = s.items[:n]
+	s.base += keep
+	s.pos -= keep
+}
+
+// Peek returns the item `n` positions ahead of the stream without consuming it, where
+// `Peek(0)` is the item that the next `Next()` call returns
+//
+// If the stream has ended before reaching that item, the EOF item is returned
+func (s *TokenStream[C, T]) Peek(n int) Item[C, T] {
+	if n < 0 {
+		n = 0
+	}
+	s.fill(n + 1)
+	if s.pos+n >= len(s.items) {
+		return s.eof
+	}
+	return s.items[s.pos+n]
+}
+
+// Next consumes and returns the next item in the stream
+//
+// Once the stream has ended, the EOF item is returned
+func (s *TokenStream[C, T]) Next() Item[C, T] {
+	item := s.Peek(0)
+	if s.pos < len(s.items) {
+		s.pos++
+	}
+	s.compact()
+	return item
+}
+
+// NextItem implements the Emitter interface, by consuming and returning the next item in
+// the stream
+func (s *TokenStream[C, T]) NextItem() Item[C, T] {
+	return s.Next()
+}
+
+// Accept consumes the next item if it is of token type `itemType`, returning true
+//
+// Otherwise, the item is not consumed and false is returned
+func (s *TokenStream[C, T]) Accept(itemType C) bool {
+	if s.Peek(0).Type != itemType {
+		return false
+	}
+	s.Next()
+	return true
+}
+
+// Expect consumes and returns the next item if it is of one of the token types `types`
+//
+// Otherwise, the item is not consumed and it is returned alongside an *UnexpectedItemError
+func (s *TokenStream[C, T]) Expect(types ...C) (Item[C, T], error) {
+	item := s.Peek(0)
+	for _, typ := range types {
+		if item.Type == typ {
+			s.Next()
+			return item, nil
+		}
+	}
+	return item, &UnexpectedItemError[C, T]{
+		Item:     item,
+		Expected: types,
+	}
+}
+
+// Mark returns a marker for the stream's current position, that can be later used to
+// rewind the stream with `Reset()`
+//
+// Markers are the number of items consumed from the stream, so they keep pointing to the same
+// item as the buffer is compacted. While a mark is active, the items after it are retained in
+// the stream's buffer; so each mark should be released with `Release()` once it is no longer
+// needed
+func (s *TokenStream[C, T]) Mark() int {
+	mark := s.base + s.pos
+	s.marks = append(s.marks, mark)
+	return mark
+}
+
+// Reset rewinds (or forwards) the stream to the position of the marker `mark`, returned
+// from a `Mark()` call
+//
+// It returns an error wrapping ErrInvalidMark, without moving the stream, if the marker is
+// outside of the buffered items' range; such as a released marker whose items were dropped
+func (s *TokenStream[C, T]) Reset(mark int) error {
+	idx := mark - s.base
+	if idx < 0 || idx > len(s.items) {
+		return fmt.Errorf("%w: %d is not buffered", ErrInvalidMark, mark)
+	}
+	s.pos = idx
+	return nil
+}
+
+// Release discards the marker `mark`, allowing the stream to drop the consumed items up to the
+// oldest of the remaining active marks
+//
+// It returns an error wrapping ErrInvalidMark if the marker is not active
+func (s *TokenStream[C, T]) Release(mark int) error {
+	for idx := range s.marks {
+		if s.marks[idx] == mark {
+			s.marks = append(s.marks[:idx], s.marks[idx+1:]...)
+			s.compact()
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %d is not active", ErrInvalidMark, mark)
+}
+
+// Discard drops the buffered lookahead items (the ones pulled from the Emitter but not yet
+// consumed), so that they are pulled again from the Emitter; such as after relexing them
+func (s *TokenStream[C, T]) Discard() {
+	s.items = s.items[:s.pos]
+	s.done = false
+	s.eof = Item[C, T]{}
+}
+
+// SetHint sets the hint `hint` on the underlying Emitter, if it is a Hinter
+func (s *TokenStream[C, T]) SetHint(hint any) {
+	if h, ok := s.emitter.(Hinter[C, T]); ok {
+		h.SetHint(hint)
+	}
+}
+
+// Relex rewinds the stream and the underlying Emitter (which must be a Hinter) to the position
+// of the item `item`, so that it is lexed again with the StateFn `state`
+//
+// Any buffered items on (or after) the item's position are dropped, and the stream's position
+// is moved back to where that item was, if it was already consumed. If the underlying Emitter
+// is not a Hinter, an error wrapping ErrRelex is returned; as it is if the Emitter no longer holds
+// the item's units (such as a LexBuffer, for items before the last one pulled by the stream)
+func (s *TokenStream[C, T]) Relex(item Item[C, T], state StateFn[C, T]) error {
+	h, ok := s.emitter.(Hinter[C, T])
+	if !ok {
+		return fmt.Errorf("%w: emitter of type %T does not take hints", ErrRelex, s.emitter)
+	}
+	if err := h.Relex(item, state); err != nil {
+		return err
+	}
+
+	idx := len(s.items)
+	for i := range s.items {
+		if s.items[i].Pos >= item.Pos {
+			idx = i
+			break
+		}
+	}
+	if s.pos > idx {
+		s.pos = idx
+	}
+	s.items = s.items[:idx]
+	s.done = false
+	s.eof = Item[C, T]{}
+	return nil
+}
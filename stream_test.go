@@ -0,0 +1,132 @@
+package lex_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zalgonoise/lex"
+)
+
+func TestTokenStream(t *testing.T) {
+	// input: `lexing.data.`
+	t.Run("Peek", func(t *testing.T) {
+		s := lex.NewTokenStream[uint, rune](lex.New(initState[uint, rune], testInput2))
+
+		for idx, wants := range []uint{tokenIdent, tokenPeriod, tokenIdent, tokenPeriod, tokenEOF, tokenEOF} {
+			if i := s.Peek(idx); i.Type != wants {
+				t.Errorf("unexpected token type on peek #%d: wanted %d ; got %d", idx, wants, i.Type)
+			}
+		}
+		if i := s.Next(); string(i.Value) != "lexing" {
+			t.Errorf("unexpected output value: wanted `%s` ; got `%s`", "lexing", string(i.Value))
+		}
+		if i := s.Peek(1); string(i.Value) != "data" {
+			t.Errorf("unexpected output value: wanted `%s` ; got `%s`", "data", string(i.Value))
+		}
+	})
+
+	t.Run("AcceptAndExpect", func(t *testing.T) {
+		s := lex.NewTokenStream[uint, rune](lex.New(initState[uint, rune], testInput2))
+
+		if s.Accept(tokenPeriod) {
+			t.Errorf("expected accept to fail on an ident token")
+		}
+		if !s.Accept(tokenIdent) {
+			t.Errorf("expected accept to succeed on an ident token")
+		}
+
+		i, err := s.Expect(tokenIdent, tokenEOF)
+		if err == nil {
+			t.Errorf("expected error not to be nil")
+		}
+		if !errors.Is(err, lex.ErrUnexpectedItem) {
+			t.Errorf("unexpected error: wanted %v ; got %v", lex.ErrUnexpectedItem, err)
+		}
+		var itemErr *lex.UnexpectedItemError[uint, rune]
+		if !errors.As(err, &itemErr) || itemErr.Item.Type != tokenPeriod || itemErr.Item.Pos != 6 {
+			t.Errorf("unexpected error item: %v", err)
+		}
+		if i.Type != tokenPeriod {
+			t.Errorf("unexpected token type: wanted %d ; got %d", tokenPeriod, i.Type)
+		}
+
+		i, err = s.Expect(tokenPeriod)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if i.Type != tokenPeriod {
+			t.Errorf("unexpected token type: wanted %d ; got %d", tokenPeriod, i.Type)
+		}
+	})
+
+	t.Run("MarkAndReset", func(t *testing.T) {
+		s := lex.NewTokenStream[uint, rune](lex.New(initState[uint, rune], testInput2))
+
+		s.Next()
+		mark := s.Mark()
+		s.Next()
+		s.Next()
+		s.Next()
+		if i := s.Next(); i.Type != tokenEOF {
+			t.Errorf("unexpected token type: wanted %d ; got %d", tokenEOF, i.Type)
+		}
+
+		s.Reset(mark)
+		s.Release(mark)
+		if i := s.Next(); i.Type != tokenPeriod || i.Pos != 6 {
+			t.Errorf("unexpected item after reset: wanted period on position %d ; got %d on position %d", 6, i.Type, i.Pos)
+		}
+		if i := s.Next(); string(i.Value) != "data" {
+			t.Errorf("unexpected output value: wanted `%s` ; got `%s`", "data", string(i.Value))
+		}
+	})
+
+	t.Run("MultipleMarks", func(t *testing.T) {
+		s := lex.NewTokenStream[uint, rune](lex.New(initState[uint, rune], testInput2))
+
+		s.Next()
+		first := s.Mark()
+		s.Next()
+		second := s.Mark()
+		s.Next()
+		s.Next()
+
+		// releasing the first mark drops the items before the second one
+		if err := s.Release(first); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := s.Release(first); !errors.Is(err, lex.ErrInvalidMark) {
+			t.Errorf("unexpected error releasing twice: wanted %v ; got %v", lex.ErrInvalidMark, err)
+		}
+		if err := s.Reset(first); !errors.Is(err, lex.ErrInvalidMark) {
+			t.Errorf("unexpected error resetting a stale mark: wanted %v ; got %v", lex.ErrInvalidMark, err)
+		}
+
+		if err := s.Reset(second); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if i := s.Next(); string(i.Value) != "data" || i.Pos != 7 {
+			t.Errorf("unexpected item after reset: wanted `%s` on position %d ; got `%s` on position %d", "data", 7, string(i.Value), i.Pos)
+		}
+
+		// a mark taken after compaction still points to its own item
+		third := s.Mark()
+		s.Next()
+		if err := s.Release(second); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := s.Reset(third); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if i := s.Next(); i.Type != tokenPeriod || i.Pos != 11 {
+			t.Errorf("unexpected item after reset: wanted period on position %d ; got %d on position %d", 11, i.Type, i.Pos)
+		}
+
+		if err := s.Release(third); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := s.Reset(second); !errors.Is(err, lex.ErrInvalidMark) {
+			t.Errorf("unexpected error resetting a released mark: wanted %v ; got %v", lex.ErrInvalidMark, err)
+		}
+	})
+}
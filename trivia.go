@@ -0,0 +1,105 @@
+package lex
+
+// TriviaMode defines how a lexer handles the units skipped with `Ignore()` (such as whitespace
+// or comments) and the items emitted on a hidden channel; either discarding them or attaching
+// them to the emitted items, so that the input can be reproduced with `Reconstruct()`
+type TriviaMode uint8
+
+const (
+	// TriviaNone discards the ignored units (the default)
+	TriviaNone TriviaMode = iota
+	// TriviaLeading attaches the ignored units to the next emitted item, as its Leading trivia
+	TriviaLeading
+	// TriviaTrailing attaches the ignored units to the previously emitted item, as its Trailing
+	// trivia. Ignored units preceding the first item are attached to it as Leading trivia
+	TriviaTrailing
+)
+
+// trivia keeps track of the ignored units in a lexer, attaching them to its items according to
+// the configured TriviaMode
+type trivia[C comparable, T any] struct {
+	mode    TriviaMode
+	pending []T
+	held    Item[C, T]
+	holding bool
+}
+
+// ignore stores a copy of the ignored units `units`, if trivia is enabled
+func (t *trivia[C, T]) ignore(units []T) {
+	if t.mode == TriviaNone || len(units) == 0 {
+		return
+	}
+	t.pending = append(t.pending, units...)
+}
+
+// hide stores a copy of the value of the item `item`, emitted on a hidden channel, as ignored
+// units; so that the hidden items' units are not lost from the emitted items' trivia
+func (t *trivia[C, T]) hide(item Item[C, T]) {
+	t.ignore(item.Value)
+}
+
+// attach adds the pending trivia to the emitted item `item`, returning the item to push to the
+// items channel, if any
+//
+// In TriviaTrailing mode, the emitted item is held until the next item is emitted (or the lexer
+// is done), and the previously held item is returned instead
+func (t *trivia[C, T]) attach(item Item[C, T]) (Item[C, T], bool) {
+	switch t.mode {
+	case TriviaLeading:
+		item.Leading, t.pending = t.pending, nil
+	case TriviaTrailing:
+		if !t.holding {
+			item.Leading, t.pending = t.pending, nil
+			t.held, t.holding = item, true
+			return Item[C, T]{}, false
+		}
+		item, t.held = t.held, item
+		item.Trailing, t.pending = t.pending, nil
+	}
+	return item, true
+}
+
+// flush returns the held item with any pending trivia, if any
+func (t *trivia[C, T]) flush() (Item[C, T], bool) {
+	if !t.holding {
+		return Item[C, T]{}, false
+	}
+	item := t.held
+	item.Trailing, t.pending = t.pending, nil
+	t.held, t.holding = Item[C, T]{}, false
+	return item, true
+}
+
+// reset discards the held item and any pending trivia, replacing it with the units `leading`
+func (t *trivia[C, T]) reset(leading []T) {
+	t.held, t.holding = Item[C, T]{}, false
+	t.pending = nil
+	t.ignore(leading)
+}
+
+// eof attaches any pending trivia to the EOF item `item`, as its Leading trivia
+func (t *trivia[C, T]) eof(item Item[C, T]) Item[C, T] {
+	if len(t.pending) > 0 {
+		item.Leading, t.pending = t.pending, nil
+	}
+	return item
+}
+
+// Reconstruct rebuilds the input data from the items `items`, by joining each item's Leading
+// trivia, Value and Trailing trivia, in order
+//
+// For a lexer that uses a TriviaMode other than TriviaNone and never skips units without
+// `Ignore()` (or an item on a hidden channel), this reproduces the original input exactly
+func Reconstruct[C comparable, T any](items []Item[C, T]) []T {
+	var size int
+	for _, item := range items {
+		size += len(item.Leading) + len(item.Value) + len(item.Trailing)
+	}
+	out := make([]T, 0, size)
+	for _, item := range items {
+		out = append(out, item.Leading...)
+		out = append(out, item.Value...)
+		out = append(out, item.Trailing...)
+	}
+	return out
+}
@@ -0,0 +1,133 @@
+package lex_test
+
+import (
+	"testing"
+
+	"github.com/zalgonoise/gbuf"
+	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+)
+
+// triviaState describes a StateFn that emits words, ignoring the whitespace between them
+func triviaState[C uint, T rune](l lex.Lexer[C, T]) lex.StateFn[C, T] {
+	l.AcceptRun(func(item T) bool { return item == ' ' || item == '\n' })
+	l.Ignore()
+	l.AcceptRun(func(item T) bool { return item != ' ' && item != '\n' && item != 0 })
+	if l.Width() == 0 {
+		l.Emit((C)(tokenEOF))
+		return nil
+	}
+	l.Emit((C)(tokenIdent))
+	return triviaState[C, T]
+}
+
+func TestTrivia(t *testing.T) {
+	input := "  lexing\n data  \n"
+
+	for _, test := range []struct {
+		name     string
+		mode     lex.TriviaMode
+		leading  []string
+		trailing []string
+	}{
+		{
+			name:     "None",
+			mode:     lex.TriviaNone,
+			leading:  []string{"", "", ""},
+			trailing: []string{"", "", ""},
+		},
+		{
+			name:     "Leading",
+			mode:     lex.TriviaLeading,
+			leading:  []string{"  ", "\n ", "  \n"},
+			trailing: []string{"", "", ""},
+		},
+		{
+			name:     "Trailing",
+			mode:     lex.TriviaTrailing,
+			leading:  []string{"  ", "", ""},
+			trailing: []string{"\n ", "  \n", ""},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			l := lex.New(triviaState[uint, rune], []rune(input))
+			l.Trivia(test.mode)
+			b := lex.NewBuffer(triviaState[uint, rune], (gio.Reader[rune])(gbuf.NewReader([]rune(input))))
+			b.Trivia(test.mode)
+
+			for _, e := range []lex.Emitter[uint, rune]{l, b} {
+				items := lex.Collect(e)
+				if len(items) != 3 {
+					t.Errorf("token slice length mismatch error: wanted %d ; got %d", 3, len(items))
+					return
+				}
+				for idx, item := range items {
+					if string(item.Leading) != test.leading[idx] {
+						t.Errorf("unexpected leading trivia on item #%d: wanted %q ; got %q", idx, test.leading[idx], string(item.Leading))
+					}
+					if string(item.Trailing) != test.trailing[idx] {
+						t.Errorf("unexpected trailing trivia on item #%d: wanted %q ; got %q", idx, test.trailing[idx], string(item.Trailing))
+					}
+				}
+				if test.mode == lex.TriviaNone {
+					continue
+				}
+				if output := string(lex.Reconstruct(items)); output != input {
+					t.Errorf("unexpected output: wanted %q ; got %q", input, output)
+				}
+			}
+		})
+	}
+}
+
+func TestTriviaHidden(t *testing.T) {
+	input := "a #x\nb #y"
+
+	for _, test := range []struct {
+		name     string
+		mode     lex.TriviaMode
+		leading  []string
+		trailing []string
+	}{
+		{
+			name:     "Leading",
+			mode:     lex.TriviaLeading,
+			leading:  []string{"", " #x\n", " #y"},
+			trailing: []string{"", "", ""},
+		},
+		{
+			name:     "Trailing",
+			mode:     lex.TriviaTrailing,
+			leading:  []string{"", "", ""},
+			trailing: []string{" #x\n", " #y", ""},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			l := lex.New(channelState[uint, rune], []rune(input))
+			l.Route(tokenComment, lex.HiddenChannel)
+			l.Trivia(test.mode)
+			b := lex.NewBuffer(channelState[uint, rune], (gio.Reader[rune])(gbuf.NewReader([]rune(input))))
+			b.Route(tokenComment, lex.HiddenChannel)
+			b.Trivia(test.mode)
+
+			for _, e := range []lex.Emitter[uint, rune]{l, b} {
+				items := lex.Collect(e)
+				if len(items) != 3 {
+					t.Errorf("token slice length mismatch error: wanted %d ; got %d", 3, len(items))
+					return
+				}
+				for idx, item := range items {
+					if string(item.Leading) != test.leading[idx] {
+						t.Errorf("unexpected leading trivia on item #%d: wanted %q ; got %q", idx, test.leading[idx], string(item.Leading))
+					}
+					if string(item.Trailing) != test.trailing[idx] {
+						t.Errorf("unexpected trailing trivia on item #%d: wanted %q ; got %q", idx, test.trailing[idx], string(item.Trailing))
+					}
+				}
+				if output := string(lex.Reconstruct(items)); output != input {
+					t.Errorf("unexpected output: wanted %q ; got %q", input, output)
+				}
+			}
+		})
+	}
+}
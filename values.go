@@ -0,0 +1,132 @@
+package lex
+
+// AcceptValue consumes the next unit if it is equal to `value`, returning true
+//
+// It is a companion to the Lexer's `Accept()` method, for lexers over comparable units (such as
+// enum events or opcodes), that do not require a validator function
+func AcceptValue[C comparable, T comparable](l Lexer[C, T], value T) bool {
+	return l.Accept(func(item T) bool {
+		return item == value
+	})
+}
+
+// AcceptValues consumes the next unit if it is equal to any of the `values`, returning true
+func AcceptValues[C comparable, T comparable](l Lexer[C, T], values ...T) bool {
+	return l.Accept(func(item T) bool {
+		for _, v := range values {
+			if item == v {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// AcceptSeq consumes the units in the sequence `seq`, in order, returning true if all of
+// them match the input
+//
+// If any unit does not match (or the end of the input is reached), the cursor is rolled back
+// to where it was, and false is returned
+func AcceptSeq[C comparable, T comparable](l Lexer[C, T], seq []T) bool {
+	pos := l.Pos()
+	for _, unit := range seq {
+		if !AcceptValue(l, unit) {
+			rewind(l, pos)
+			return false
+		}
+	}
+	return true
+}
+
+// AcceptAny consumes the longest of the sequences in `seqs` that matches the input, returning
+// its index; or -1 if none of them match, in which case the cursor is not moved
+func AcceptAny[C comparable, T comparable](l Lexer[C, T], seqs [][]T) int {
+	pos := l.Pos()
+	match := -1
+	var longest int
+	for idx, seq := range seqs {
+		if (match < 0 || len(seq) > longest) && AcceptSeq(l, seq) {
+			match = idx
+			longest = len(seq)
+			rewind(l, pos)
+		}
+	}
+	if match >= 0 {
+		AcceptSeq(l, seqs[match])
+	}
+	return match
+}
+
+// AcceptUntilSeq consumes all units up to (but not including) the first occurrence of the
+// sequence `seq`, returning true
+//
+// If the end of the input is reached first, the cursor is rolled back to where it was,
+// and false is returned
+func AcceptUntilSeq[C comparable, T comparable](l Lexer[C, T], seq []T) bool {
+	pos := l.Pos()
+	for {
+		next := l.Pos()
+		if AcceptSeq(l, seq) {
+			rewind(l, next)
+			return true
+		}
+		if !l.Accept(func(T) bool { return true }) {
+			// EOF
+			rewind(l, pos)
+			return false
+		}
+	}
+}
+
+// IndexOf returns the index of the next unit equal to `value`, starting from the cursor's
+// position, without moving the cursor; or -1 if there is no such unit
+//
+// The index is in the same reference as the Lexer's `Pos()` and `Extract()` methods
+func IndexOf[C comparable, T comparable](l Lexer[C, T], value T) int {
+	pos := l.Pos()
+	if !acceptUntil(l, func(item T) bool {
+		return item == value
+	}) {
+		return -1
+	}
+	idx := l.Pos()
+	rewind(l, pos)
+	return idx
+}
+
+// AcceptTo consumes all units up to (but not including) the index `idx`, as returned by
+// `IndexOf()`, returning true
+//
+// If the index is behind the cursor or past the end of the input, the cursor is not moved,
+// and false is returned
+func AcceptTo[C comparable, T any](l Lexer[C, T], idx int) bool {
+	pos := l.Pos()
+	if idx < pos {
+		return false
+	}
+	for l.Pos() < idx {
+		if !l.Accept(func(T) bool { return true }) {
+			rewind(l, pos)
+			return false
+		}
+	}
+	return true
+}
+
+// CheckSeq returns true if the upcoming units, starting from the cursor's position, match the
+// sequence `seq`; without moving the cursor
+func CheckSeq[C comparable, T comparable](l Lexer[C, T], seq []T) bool {
+	pos := l.Pos()
+	if !AcceptSeq(l, seq) {
+		return false
+	}
+	rewind(l, pos)
+	return true
+}
+
+// rewind moves the lexer's cursor back to the position `pos`, behind the current position
+func rewind[C comparable, T any](l Lexer[C, T], pos int) {
+	if l.Pos() != pos {
+		l.Idx(pos)
+	}
+}
@@ -0,0 +1,86 @@
+package lex_test
+
+import (
+	"testing"
+
+	"github.com/zalgonoise/gbuf"
+	"github.com/zalgonoise/gio"
+	"github.com/zalgonoise/lex"
+	"github.com/zalgonoise/lex/lextest"
+)
+
+type opcode uint8
+
+const (
+	opNop opcode = iota + 1
+	opPush
+	opPop
+	opCall
+	opRet
+)
+
+const (
+	tokenOpEOF uint8 = iota
+	tokenOpFrame
+	tokenOpOther
+)
+
+// opState describes a StateFn that groups `call ... ret` opcode sequences into frames
+func opState(l lex.Lexer[uint8, opcode]) lex.StateFn[uint8, opcode] {
+	switch {
+	case lex.CheckSeq(l, []opcode{opCall, opRet}):
+		l.(lex.Acceptor[opcode]).AcceptN(2, func(opcode) bool { return true })
+		l.Emit(tokenOpFrame)
+	case lex.AcceptValue(l, opCall):
+		if idx := lex.IndexOf(l, opRet); idx >= 0 {
+			lex.AcceptTo(l, idx)
+			lex.AcceptValue(l, opRet)
+			l.Emit(tokenOpFrame)
+			return opState
+		}
+		l.AcceptRun(func(opcode) bool { return true })
+		l.Emit(tokenOpOther)
+	case lex.AcceptValues(l, opNop, opPush, opPop, opRet):
+		l.Emit(tokenOpOther)
+	default:
+		l.Emit(tokenOpEOF)
+		return nil
+	}
+	return opState
+}
+
+func TestValues(t *testing.T) {
+	input := []opcode{opNop, opCall, opRet, opPush, opCall, opPush, opPop, opRet, opCall, opNop, opCall, opPop, opRet}
+	wants := []lex.Item[uint8, opcode]{
+		lex.NewItem(0, tokenOpOther, opNop),
+		lex.NewItem(1, tokenOpFrame, opCall, opRet),
+		lex.NewItem(3, tokenOpOther, opPush),
+		lex.NewItem(4, tokenOpFrame, opCall, opPush, opPop, opRet),
+		lex.NewItem(8, tokenOpFrame, opCall, opNop, opCall, opPop, opRet),
+		lex.NewItem[uint8, opcode](13, tokenOpEOF),
+	}
+
+	t.Run("Lex", func(t *testing.T) {
+		lextest.Compare(t, wants, lex.Collect[uint8, opcode](lex.New(opState, input)))
+	})
+	t.Run("LexBuffer", func(t *testing.T) {
+		lextest.Compare(t, wants, lex.Collect[uint8, opcode](
+			lex.NewBuffer(opState, (gio.Reader[opcode])(gbuf.NewReader(input))),
+		))
+	})
+	t.Run("Unterminated", func(t *testing.T) {
+		lextest.Compare(t, []lex.Item[uint8, opcode]{
+			lex.NewItem(0, tokenOpOther, opCall, opNop),
+			lex.NewItem[uint8, opcode](2, tokenOpEOF),
+		}, lex.Collect[uint8, opcode](lex.New(opState, []opcode{opCall, opNop})))
+	})
+	t.Run("AcceptTo", func(t *testing.T) {
+		l := lex.New(opState, input)
+		if lex.AcceptTo[uint8, opcode](l, len(input)+1) || l.Pos() != 0 {
+			t.Errorf("expected units not to be accepted past the end of the input ; pos: %d", l.Pos())
+		}
+		if !lex.AcceptTo[uint8, opcode](l, len(input)) || l.Pos() != len(input) {
+			t.Errorf("expected units to be accepted up to the end of the input ; pos: %d", l.Pos())
+		}
+	})
+}